@@ -2,43 +2,196 @@ package main
 
 import (
 	"context"
-	"os/exec"
+	"errors"
+	"fmt"
+	"io"
+	"os"
 	"strings"
+	"sync"
+	"time"
 
-	"golang.org/x/text/encoding/simplifiedchinese"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 
 	"network-rescue-toolkit/internal/diagnostic"
 	"network-rescue-toolkit/internal/repair"
+	"network-rescue-toolkit/pkg/audit"
 	"network-rescue-toolkit/pkg/backup"
+	"network-rescue-toolkit/pkg/executor"
 	"network-rescue-toolkit/pkg/privilege"
+	"network-rescue-toolkit/pkg/reboot"
+	"network-rescue-toolkit/pkg/registry"
 	"network-rescue-toolkit/pkg/report"
 	"network-rescue-toolkit/pkg/types"
 )
 
+// AppVersion 程序版本（与 wails.json 中的 productVersion 保持一致）
+const AppVersion = "1.0.0"
+
+// 网络工具实时输出的事件名称
+const (
+	EventToolOutput = "tool:output" // 工具输出一行，数据为 ToolOutput
+	EventToolDone   = "tool:done"   // 工具运行结束，数据为工具名称
+)
+
+// EventDiagnostic 诊断进度的事件名称，数据为 diagnostic.Event
+const EventDiagnostic = "diagnostic:event"
+
+// EventRebootVerified 重启后自动重新诊断完成的事件名称，数据为 reboot.Verification
+const EventRebootVerified = "reboot:verified"
+
+// ToolOutput 网络工具的一行实时输出
+type ToolOutput struct {
+	Tool string `json:"tool"`
+	Line string `json:"line"`
+}
+
+// toolRun 正在运行的网络工具
+type toolRun struct {
+	cancel context.CancelFunc
+}
+
 // App 应用主结构
 type App struct {
 	ctx              context.Context
+	emit             func(event string, data ...interface{})
+	runner           executor.Runner
+	toolRuns         map[string]*toolRun
+	toolMu           sync.Mutex
 	diagnosticEngine *diagnostic.Engine
+	analyzer         *diagnostic.Analyzer
 	repairEngine     *repair.Engine
 	backupManager    *backup.Manager
 	reportGenerator  *report.Generator
 	privilegeHelper  *privilege.Helper
+	pendingReboot    *reboot.Store
+	loginHook        *reboot.LoginHook
+	rebootResult     *reboot.Verification
+	rebootMu         sync.Mutex
+	readOnly         string // 不为空时禁止修改本机配置，为拒绝的原因
 }
 
+// replayReadOnly 回放模式下拒绝修改配置的原因
+// 回放的只是外部命令，HOSTS 文件、注册表和备份仍是本机的，修复或还原会改动本机而不是录制的机器
+const replayReadOnly = "回放模式只用于重现诊断结果，不会修改本机配置"
+
 // NewApp 创建新的应用实例
 func NewApp() *App {
-	return &App{
-		diagnosticEngine: diagnostic.NewEngine(),
-		repairEngine:     repair.NewEngine(),
-		backupManager:    backup.NewManager(),
+	// 所有外部命令和注册表、文件写入都记录到 ~/.network-rescue-toolkit/logs
+	audit.SetDefault(audit.NewDefaultLogger())
+	app := NewAppWithRunner(commandRunnerFromEnv())
+	if os.Getenv("NETWORK_RESCUE_REPLAY") != "" {
+		app.setReadOnly(replayReadOnly)
+	}
+	return app
+}
+
+// NewAppWithRunner 使用指定的命令执行器创建应用实例
+func NewAppWithRunner(runner executor.Runner) *App {
+	store := registry.NewDefaultStore()
+	app := &App{
+		runner:           runner,
+		toolRuns:         make(map[string]*toolRun),
+		diagnosticEngine: diagnostic.NewEngineWithRunner(runner),
+		analyzer:         diagnostic.NewAnalyzer(),
+		repairEngine:     repair.NewEngineWithRunner(runner, store),
+		backupManager:    backup.NewManagerWithRunner(runner, store),
 		reportGenerator:  report.NewGenerator(),
 		privilegeHelper:  privilege.NewHelper(),
+		pendingReboot:    reboot.NewDefaultStore(),
+		loginHook:        reboot.NewLoginHook(store),
 	}
+	// 修复前快照与手动备份共用同一个备份管理器
+	app.backupManager.SetAppVersion(AppVersion)
+	app.repairEngine.SetBackupManager(app.backupManager)
+	// 修复后重新运行相关诊断项，确认问题确实已解决
+	app.repairEngine.SetVerifier(app.diagnosticEngine.Recheck)
+	// 每个检查项开始和完成时通知前端，结果逐项显示
+	app.diagnosticEngine.OnEvent(func(event diagnostic.Event) {
+		app.emitEvent(EventDiagnostic, event)
+	})
+	return app
+}
+
+// setReadOnly 禁止修复、还原备份和撤销等修改本机配置的操作，reason 为空时恢复正常
+func (a *App) setReadOnly(reason string) {
+	a.readOnly = reason
+	a.repairEngine.SetReadOnly(reason)
+}
+
+// checkWritable 只读时返回拒绝修改配置的错误
+func (a *App) checkWritable() error {
+	if a.readOnly != "" {
+		return errors.New(a.readOnly)
+	}
+	return nil
+}
+
+// commandRunnerFromEnv 根据环境变量选择命令执行方式
+// NETWORK_RESCUE_RECORD=<文件> 录制执行的所有命令；NETWORK_RESCUE_REPLAY=<文件> 回放录制的命令；
+// NETWORK_RESCUE_CODEPAGE=<代码页或编码名> 指定命令输出的编码
+// 环境变量无效时写入审计日志
+func commandRunnerFromEnv() executor.Runner {
+	ctx := audit.WithComponent(context.Background(), "app/startup")
+	if path := os.Getenv("NETWORK_RESCUE_REPLAY"); path != "" {
+		replayer, err := executor.NewReplayer(path)
+		if err != nil {
+			// 回放文件无效时不能退回真实执行，否则会修改本机配置
+			audit.Default().LogError(ctx, "NETWORK_RESCUE_REPLAY", fmt.Errorf("加载回放文件失败: %w", err))
+			return executor.NewFakeRunner()
+		}
+		return replayer
+	}
+
+	runner := executor.NewCommandExecutor()
+	if name := os.Getenv("NETWORK_RESCUE_CODEPAGE"); name != "" {
+		if enc, ok := executor.ParseEncoding(name); ok {
+			runner.SetEncoding(enc)
+		} else {
+			audit.Default().LogError(ctx, "NETWORK_RESCUE_CODEPAGE", fmt.Errorf("不支持的代码页: %s", name))
+		}
+	}
+	if path := os.Getenv("NETWORK_RESCUE_RECORD"); path != "" {
+		return executor.NewRecorder(runner, path)
+	}
+	return runner
 }
 
 // startup 应用启动时调用
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	a.emit = func(event string, data ...interface{}) {
+		runtime.EventsEmit(ctx, event, data...)
+	}
+	// 上次修复后已重启时，自动重新诊断确认修复是否生效
+	go a.verifyAfterReboot()
+}
+
+// shutdown 应用退出时调用，录制模式下保存录制文件
+func (a *App) shutdown(ctx context.Context) {
+	closer, ok := a.runner.(io.Closer)
+	if !ok {
+		return
+	}
+	start := time.Now()
+	if err := closer.Close(); err != nil {
+		audit.Default().LogWrite(audit.WithComponent(ctx, "app/record"), audit.ActionFileWrite, os.Getenv("NETWORK_RESCUE_RECORD"), nil, start, err)
+	}
+}
+
+// context 返回网络工具使用的上下文（启动前调用时使用后台上下文）
+func (a *App) context() context.Context {
+	ctx := a.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return audit.WithComponent(ctx, "app/tools")
+}
+
+// emitEvent 向前端发送事件（未启动 Wails 时忽略）
+func (a *App) emitEvent(event string, data ...interface{}) {
+	if a.emit != nil {
+		a.emit(event, data...)
+	}
 }
 
 // IsAdmin 检查是否以管理员权限运行
@@ -46,24 +199,173 @@ func (a *App) IsAdmin() bool {
 	return a.privilegeHelper.IsAdmin()
 }
 
-// RunDiagnostic 运行完整诊断
+// RunDiagnostic 运行完整诊断，运行过程中通过 EventDiagnostic 事件推送各检查项的进度
 func (a *App) RunDiagnostic() []types.DiagnosticResult {
 	return a.diagnosticEngine.RunAll(a.ctx)
 }
 
+// SetDiagnosticConcurrency 设置诊断时同时运行的检查项数量
+func (a *App) SetDiagnosticConcurrency(n int) {
+	a.diagnosticEngine.SetConcurrency(n)
+}
+
 // RunSingleDiagnostic 运行单个诊断项
 func (a *App) RunSingleDiagnostic(id string) types.DiagnosticResult {
 	return a.diagnosticEngine.RunSingle(a.ctx, id)
 }
 
+// AnalyzeDiagnostic 分析最近一次诊断结果，按可能性从高到低返回故障根因
+func (a *App) AnalyzeDiagnostic() []types.RootCause {
+	return a.analyzer.Analyze(a.diagnosticEngine.GetResults())
+}
+
 // RunRepair 执行修复操作
 func (a *App) RunRepair(id string) types.RepairResult {
-	return a.repairEngine.Repair(a.ctx, id)
+	result := a.repairEngine.Repair(a.ctx, id)
+	a.recordRebootPending([]types.RepairResult{result})
+	return result
+}
+
+// RepairAdapters 重置指定网卡；名称和索引都为空时只重置异常的物理网卡
+func (a *App) RepairAdapters(selection repair.AdapterSelection) types.RepairResult {
+	result := a.repairEngine.RepairAdapters(a.ctx, selection)
+	a.recordRebootPending([]types.RepairResult{result})
+	return result
 }
 
 // RunComprehensiveRepair 执行综合修复
 func (a *App) RunComprehensiveRepair() []types.RepairResult {
-	return a.repairEngine.RepairAll(a.ctx)
+	results := a.repairEngine.RepairAll(a.ctx)
+	a.recordRebootPending(results)
+	return results
+}
+
+// DryRunRepair 预演单个修复操作，只返回将要进行的修改，不做任何改动
+func (a *App) DryRunRepair(id string) types.DryRunResult {
+	return a.repairEngine.DryRun(a.ctx, id)
+}
+
+// DryRunComprehensiveRepair 预演综合修复，供确认后再执行 RunComprehensiveRepair
+func (a *App) DryRunComprehensiveRepair() []types.DryRunResult {
+	return a.repairEngine.DryRunAll(a.ctx)
+}
+
+// PlanSmartRepair 根据最近一次诊断结果生成智能修复计划（尚未诊断时先运行诊断）
+func (a *App) PlanSmartRepair() types.RepairPlan {
+	return a.repairEngine.Plan(a.latestDiagnostic())
+}
+
+// RunSmartRepair 智能修复：只执行能解决未通过诊断项的修复器，按影响程度从小到大执行，
+// 每一步后重新诊断相关项目，网络恢复后立即停止
+func (a *App) RunSmartRepair() types.RepairPlanResult {
+	plan := a.repairEngine.Plan(a.latestDiagnostic())
+	outcome := a.repairEngine.RunPlan(a.ctx, plan, a.diagnosticEngine.Recheck)
+	a.recordRebootPending(a.repairEngine.GetResults())
+	return outcome
+}
+
+// latestDiagnostic 返回最近一次诊断结果，尚未诊断时先运行完整诊断
+func (a *App) latestDiagnostic() []types.DiagnosticResult {
+	if results := a.diagnosticEngine.GetResults(); len(results) > 0 {
+		return results
+	}
+	return a.diagnosticEngine.RunAll(a.ctx)
+}
+
+// recordRebootPending 有需要重启才能生效的修复时，保存待验证记录和重启前的诊断结果
+func (a *App) recordRebootPending(results []types.RepairResult) {
+	record := reboot.NewRecord(results, a.diagnosticEngine.GetResults())
+	if record == nil {
+		return
+	}
+	start := time.Now()
+	if err := a.pendingReboot.Save(*record); err != nil {
+		audit.Default().LogWrite(audit.WithComponent(a.context(), "app/reboot"), audit.ActionFileWrite, a.pendingReboot.Path(), nil, start, err)
+	}
+}
+
+// GetPendingRebootVerification 返回等待重启后验证的修复记录，没有时返回 nil
+func (a *App) GetPendingRebootVerification() (*reboot.Record, error) {
+	return a.pendingReboot.Load()
+}
+
+// SetVerifyAtNextLogin 设置或取消下次登录时自动启动本程序，以便重启后验证修复
+func (a *App) SetVerifyAtNextLogin(enabled bool) error {
+	if err := a.checkWritable(); err != nil {
+		return err
+	}
+	if !enabled {
+		return a.loginHook.Cancel()
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("获取程序路径失败: %w", err)
+	}
+	return a.loginHook.Schedule(exe)
+}
+
+// GetRebootVerification 返回本次启动时重启后验证的结论，未进行验证时返回 nil
+func (a *App) GetRebootVerification() *reboot.Verification {
+	a.rebootMu.Lock()
+	defer a.rebootMu.Unlock()
+	return a.rebootResult
+}
+
+// verifyAfterReboot 系统已在修复后重启时重新运行完整诊断，与重启前的结果比较并通知前端
+func (a *App) verifyAfterReboot() {
+	if a.readOnly != "" {
+		// 待验证记录属于本机，不能和回放的诊断结果比较
+		return
+	}
+	ctx := audit.WithComponent(a.context(), "app/reboot")
+	record, err := a.pendingReboot.Load()
+	if err != nil {
+		audit.Default().LogError(ctx, a.pendingReboot.Path(), err)
+		return
+	}
+	if record == nil || !record.Due(reboot.BootTime()) {
+		return
+	}
+
+	verification := reboot.Compare(*record, a.diagnosticEngine.RunAll(a.ctx))
+	a.rebootMu.Lock()
+	a.rebootResult = &verification
+	a.rebootMu.Unlock()
+
+	start := time.Now()
+	if err := a.pendingReboot.Clear(); err != nil {
+		audit.Default().LogWrite(ctx, audit.ActionFileWrite, a.pendingReboot.Path(), nil, start, err)
+	}
+	// 登录启动项通常已被系统删除，手动启动时一并取消
+	a.loginHook.Cancel()
+	a.emitEvent(EventRebootVerified, verification)
+}
+
+// UndoLastRepair 撤销最近一次修复（还原修复前的快照）
+func (a *App) UndoLastRepair() (backup.RestoreReport, error) {
+	path := a.repairEngine.LastBackupPath()
+	if path == "" {
+		return backup.RestoreReport{}, fmt.Errorf("没有可撤销的修复")
+	}
+	if err := a.checkWritable(); err != nil {
+		return backup.RestoreReport{}, err
+	}
+
+	report, err := a.backupManager.RestoreBackup(path)
+	if err == nil && report.Success {
+		a.repairEngine.ClearLastBackup()
+	}
+	return report, err
+}
+
+// GetUndoStack 返回本次运行中可单独撤销的修复（代理、HOSTS 等），最近的在前
+func (a *App) GetUndoStack() []types.UndoEntry {
+	return a.repairEngine.UndoStack()
+}
+
+// UndoRepair 撤销最近一次可单独撤销的修复，只还原该修复器修改过的配置
+func (a *App) UndoRepair() (types.UndoEntry, error) {
+	return a.repairEngine.Undo(a.ctx)
 }
 
 // CreateBackup 创建配置备份
@@ -71,16 +373,79 @@ func (a *App) CreateBackup() (string, error) {
 	return a.backupManager.CreateBackup()
 }
 
+// CreateLabeledBackup 创建带标签的配置备份
+func (a *App) CreateLabeledBackup(label string) (string, error) {
+	return a.backupManager.CreateBackupWithOptions(backup.BackupOptions{
+		Label:   label,
+		Trigger: backup.TriggerManual,
+	})
+}
+
 // RestoreBackup 还原配置备份
-func (a *App) RestoreBackup(path string) error {
+func (a *App) RestoreBackup(path string) (backup.RestoreReport, error) {
+	if err := a.checkWritable(); err != nil {
+		return backup.RestoreReport{}, err
+	}
 	return a.backupManager.RestoreBackup(path)
 }
 
+// PlanRestore 预览备份与当前配置的差异
+func (a *App) PlanRestore(path string) (backup.RestorePlan, error) {
+	return a.backupManager.PlanRestore(path)
+}
+
+// RestoreBackupComponents 只还原选中的组件
+func (a *App) RestoreBackupComponents(path string, components []string) (backup.RestoreReport, error) {
+	if err := a.checkWritable(); err != nil {
+		return backup.RestoreReport{}, err
+	}
+	return a.backupManager.RestoreComponents(path, components)
+}
+
 // ListBackups 列出所有备份
 func (a *App) ListBackups() ([]backup.BackupInfo, error) {
 	return a.backupManager.ListBackups()
 }
 
+// DeleteBackup 删除备份
+func (a *App) DeleteBackup(path string) error {
+	return a.backupManager.DeleteBackup(path)
+}
+
+// PinBackup 固定或取消固定备份
+func (a *App) PinBackup(path string, pinned bool) error {
+	return a.backupManager.PinBackup(path, pinned)
+}
+
+// SetBackupLabel 修改备份标签
+func (a *App) SetBackupLabel(path, label string) error {
+	return a.backupManager.SetBackupLabel(path, label)
+}
+
+// SetBackupRetention 设置备份保留策略
+func (a *App) SetBackupRetention(policy backup.RetentionPolicy) {
+	a.backupManager.SetRetentionPolicy(policy)
+}
+
+// ExportBackups 将选中的备份导出为加密归档
+func (a *App) ExportBackups(paths []string, archivePath, password string) error {
+	return a.backupManager.ExportBackups(paths, archivePath, password)
+}
+
+// ImportBackups 从加密归档导入备份，adapterMapping 为归档中的适配器名称到本机名称的映射
+func (a *App) ImportBackups(archivePath, password string, adapterMapping map[string]string) ([]string, error) {
+	return a.backupManager.ImportBackups(archivePath, password, adapterMapping)
+}
+
+// QueryAuditLog 查询审计日志，可按时间范围和组件过滤
+func (a *App) QueryAuditLog(query audit.Query) ([]audit.Entry, error) {
+	logger := audit.Default()
+	if logger == nil {
+		return []audit.Entry{}, nil
+	}
+	return logger.Query(query)
+}
+
 // ExportReport 导出诊断报告
 func (a *App) ExportReport(format string) (string, error) {
 	results := a.diagnosticEngine.RunAll(a.ctx)
@@ -107,13 +472,14 @@ func (a *App) RunPing(target string) string {
 		target = target[:idx]
 	}
 
-	out, err := exec.Command("ping", "-n", "4", target).Output()
-	if err != nil {
-		return "Ping 失败: " + err.Error()
+	result := a.runTool("ping", time.Minute, "ping", "-n", "4", target)
+	if errors.Is(result.Error, context.Canceled) {
+		return result.Stdout + "\n\nPing 已取消"
 	}
-	// 转换编码 (GBK -> UTF8)
-	result, _ := simplifiedchinese.GBK.NewDecoder().String(string(out))
-	return result
+	if result.Error != nil {
+		return "Ping 失败: " + result.Error.Error()
+	}
+	return result.Stdout
 }
 
 // SwitchDNS 切换 DNS 服务器
@@ -126,41 +492,41 @@ func (a *App) SwitchDNS(primary, secondary string) bool {
 
 	for _, adapter := range adapters {
 		// 设置主 DNS
-		exec.Command("netsh", "interface", "ip", "set", "dns", adapter, "static", primary).Run()
+		a.runner.Execute(a.context(), "netsh", "interface", "ip", "set", "dns", adapter, "static", primary)
 		// 设置备用 DNS
-		exec.Command("netsh", "interface", "ip", "add", "dns", adapter, secondary, "index=2").Run()
+		a.runner.Execute(a.context(), "netsh", "interface", "ip", "add", "dns", adapter, secondary, "index=2")
 	}
 	return true
 }
 
 // FlushDNS 刷新 DNS 缓存
 func (a *App) FlushDNS() error {
-	return exec.Command("ipconfig", "/flushdns").Run()
+	return a.runner.Execute(a.context(), "ipconfig", "/flushdns").Error
 }
 
 // ResetNetworkStack 重置网络组件
 func (a *App) ResetNetworkStack() error {
-	exec.Command("netsh", "winsock", "reset").Run()
-	exec.Command("netsh", "int", "ip", "reset").Run()
+	a.runner.Execute(a.context(), "netsh", "winsock", "reset")
+	a.runner.Execute(a.context(), "netsh", "int", "ip", "reset")
 	return nil
 }
 
 // ReleaseRenewIP 释放并重新获取 IP
 func (a *App) ReleaseRenewIP() error {
-	exec.Command("ipconfig", "/release").Run()
-	exec.Command("ipconfig", "/renew").Run()
+	a.runner.Execute(a.context(), "ipconfig", "/release")
+	a.runner.Execute(a.context(), "ipconfig", "/renew")
 	return nil
 }
 
 // getActiveAdapters 获取活动网卡名称
 func (a *App) getActiveAdapters() []string {
 	var adapters []string
-	out, err := exec.Command("netsh", "interface", "show", "interface").Output()
-	if err != nil {
+	result := a.runner.Execute(a.context(), "netsh", "interface", "show", "interface")
+	if result.Error != nil {
 		return adapters
 	}
 
-	lines := strings.Split(string(out), "\n")
+	lines := strings.Split(result.Stdout, "\n")
 	for _, line := range lines {
 		if strings.Contains(line, "Connected") || strings.Contains(line, "已连接") {
 			fields := strings.Fields(line)
@@ -181,24 +547,26 @@ func (a *App) RunTraceroute(target string) string {
 		target = target[:idx]
 	}
 
-	out, err := exec.Command("tracert", "-d", "-h", "15", target).Output()
-	if err != nil {
-		return "路由追踪失败: " + err.Error()
+	// 逐跳超时累计可能超过执行器的默认超时
+	result := a.runTool("traceroute", 3*time.Minute, "tracert", "-d", "-h", "15", target)
+	if errors.Is(result.Error, context.Canceled) {
+		return result.Stdout + "\n\n路由追踪已取消"
 	}
-	result, _ := simplifiedchinese.GBK.NewDecoder().String(string(out))
-	return result
+	if result.Error != nil {
+		return "路由追踪失败: " + result.Error.Error()
+	}
+	return result.Stdout
 }
 
 // CheckPort 端口检测
 func (a *App) CheckPort(host string, port string) string {
 	target := host + ":" + port
-	out, err := exec.Command("powershell", "-Command",
-		"$tcp = New-Object System.Net.Sockets.TcpClient; try { $tcp.Connect('"+host+"', "+port+"); 'open' } catch { 'closed' } finally { $tcp.Close() }").Output()
-	if err != nil {
+	result := a.runner.Execute(a.context(), "powershell", "-Command",
+		"$tcp = New-Object System.Net.Sockets.TcpClient; try { $tcp.Connect('"+host+"', "+port+"); 'open' } catch { 'closed' } finally { $tcp.Close() }")
+	if result.Error != nil {
 		return target + " - 检测失败"
 	}
-	result := strings.TrimSpace(string(out))
-	if result == "open" {
+	if result.Stdout == "open" {
 		return target + " - ✓ 开放"
 	}
 	return target + " - ✗ 关闭/被封"
@@ -206,12 +574,11 @@ func (a *App) CheckPort(host string, port string) string {
 
 // GetNetworkInfo 获取网卡详细信息
 func (a *App) GetNetworkInfo() string {
-	out, err := exec.Command("ipconfig", "/all").Output()
-	if err != nil {
+	result := a.runner.Execute(a.context(), "ipconfig", "/all")
+	if result.Error != nil {
 		return "获取网络信息失败"
 	}
-	result, _ := simplifiedchinese.GBK.NewDecoder().String(string(out))
-	return result
+	return result.Stdout
 }
 
 // RestartNetworkServices 重启网络服务
@@ -220,9 +587,8 @@ func (a *App) RestartNetworkServices() string {
 	var results []string
 
 	for _, svc := range services {
-		exec.Command("net", "stop", svc).Run()
-		err := exec.Command("net", "start", svc).Run()
-		if err != nil {
+		a.runner.Execute(a.context(), "net", "stop", svc)
+		if result := a.runner.Execute(a.context(), "net", "start", svc); result.Error != nil {
 			results = append(results, svc+": 重启失败")
 		} else {
 			results = append(results, svc+": 已重启")
@@ -233,10 +599,50 @@ func (a *App) RestartNetworkServices() string {
 
 // GetFirewallStatus 获取防火墙状态
 func (a *App) GetFirewallStatus() string {
-	out, err := exec.Command("netsh", "advfirewall", "show", "allprofiles", "state").Output()
-	if err != nil {
+	result := a.runner.Execute(a.context(), "netsh", "advfirewall", "show", "allprofiles", "state")
+	if result.Error != nil {
 		return "获取防火墙状态失败"
 	}
-	result, _ := simplifiedchinese.GBK.NewDecoder().String(string(out))
-	return result
+	return result.Stdout
+}
+
+// runTool 运行网络工具，逐行通过 EventToolOutput 事件推送输出
+// 同一工具再次运行时会取消上一次运行
+func (a *App) runTool(tool string, timeout time.Duration, name string, args ...string) executor.CommandResult {
+	ctx, cancel := context.WithTimeout(audit.WithComponent(a.context(), "app/"+tool), timeout)
+	run := &toolRun{cancel: cancel}
+
+	a.toolMu.Lock()
+	if previous, ok := a.toolRuns[tool]; ok {
+		previous.cancel()
+	}
+	a.toolRuns[tool] = run
+	a.toolMu.Unlock()
+
+	defer func() {
+		a.toolMu.Lock()
+		if a.toolRuns[tool] == run {
+			delete(a.toolRuns, tool)
+		}
+		a.toolMu.Unlock()
+		cancel()
+		a.emitEvent(EventToolDone, tool)
+	}()
+
+	return executor.ExecuteStream(ctx, a.runner, func(line string) {
+		a.emitEvent(EventToolOutput, ToolOutput{Tool: tool, Line: line})
+	}, name, args...)
+}
+
+// CancelTool 取消正在运行的网络工具（ping / traceroute），返回是否有运行中的工具被取消
+func (a *App) CancelTool(tool string) bool {
+	a.toolMu.Lock()
+	defer a.toolMu.Unlock()
+
+	run, ok := a.toolRuns[tool]
+	if ok {
+		run.cancel()
+		delete(a.toolRuns, tool)
+	}
+	return ok
 }
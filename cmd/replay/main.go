@@ -0,0 +1,76 @@
+// replay 在任意系统上回放客户机器录制的命令，重现诊断结果
+//
+// 用法: go run ./cmd/replay -fixture recording.json [-check ip]
+//
+// 只有外部命令使用录制结果；网卡列表、网关探测、DNS 解析、HOSTS、代理注册表、HTTP 连通性和 IPv6 探测
+// 仍读取本机，这些检查项的结果不代表录制的机器。本工具只运行诊断，不执行任何修复
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"network-rescue-toolkit/internal/diagnostic"
+	"network-rescue-toolkit/pkg/executor"
+	"network-rescue-toolkit/pkg/types"
+)
+
+// localChecks 部分或全部数据不经过命令执行器、直接读取本机的检查项
+var localChecks = map[string]string{
+	"adapter":      "网卡列表",
+	"gateway":      "ARP/ping 探测",
+	"dns":          "DNS 解析",
+	"hosts":        "HOSTS 文件",
+	"proxy":        "代理注册表",
+	"connectivity": "HTTP 连通性",
+	"ipv6":         "AAAA 解析和 IPv6 连通性",
+}
+
+func main() {
+	fixturePath := flag.String("fixture", "", "录制文件路径")
+	checkID := flag.String("check", "", "只运行指定的检查项（默认运行全部）")
+	flag.Parse()
+
+	if *fixturePath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	replayer, err := executor.NewReplayer(*fixturePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	engine := diagnostic.NewEngineWithRunner(replayer)
+
+	var results []types.DiagnosticResult
+	if *checkID != "" {
+		results = []types.DiagnosticResult{engine.RunSingle(ctx, *checkID)}
+	} else {
+		results = engine.RunAll(ctx)
+	}
+
+	// 提示写到标准错误，标准输出保持为可解析的 JSON
+	var local []string
+	for _, result := range results {
+		if source, ok := localChecks[result.ID]; ok {
+			local = append(local, fmt.Sprintf("%s（%s）", result.ID, source))
+		}
+	}
+	if len(local) > 0 {
+		fmt.Fprintln(os.Stderr, "注意：只有外部命令使用录制结果，以下检查项读取的是本机数据，结果不代表录制的机器:", strings.Join(local, "、"))
+	}
+
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Println(string(data))
+}
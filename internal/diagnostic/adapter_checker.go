@@ -2,17 +2,23 @@ package diagnostic
 
 import (
 	"context"
+	"fmt"
 	"net"
 
+	"network-rescue-toolkit/pkg/executor"
+	"network-rescue-toolkit/pkg/netif"
 	"network-rescue-toolkit/pkg/types"
 )
 
 // AdapterChecker 网络适配器检查器
-type AdapterChecker struct{}
+type AdapterChecker struct {
+	executor executor.Runner
+	system   func() ([]netif.Adapter, error)
+}
 
 // NewAdapterChecker 创建网络适配器检查器
-func NewAdapterChecker() *AdapterChecker {
-	return &AdapterChecker{}
+func NewAdapterChecker(runner executor.Runner) *AdapterChecker {
+	return &AdapterChecker{executor: runner, system: netif.Adapters}
 }
 
 // ID 返回检查器 ID
@@ -41,7 +47,6 @@ func (c *AdapterChecker) Check(ctx context.Context) types.DiagnosticResult {
 	}
 
 	adapters := make([]types.AdapterInfo, 0)
-	activeCount := 0
 
 	for _, iface := range interfaces {
 		// 跳过回环接口
@@ -50,6 +55,7 @@ func (c *AdapterChecker) Check(ctx context.Context) types.DiagnosticResult {
 		}
 
 		adapter := types.AdapterInfo{
+			Index:      iface.Index,
 			Name:       iface.Name,
 			MACAddress: iface.HardwareAddr.String(),
 		}
@@ -57,7 +63,6 @@ func (c *AdapterChecker) Check(ctx context.Context) types.DiagnosticResult {
 		// 检查接口状态
 		if iface.Flags&net.FlagUp != 0 {
 			adapter.Status = "Up"
-			activeCount++
 		} else {
 			adapter.Status = "Down"
 		}
@@ -70,6 +75,8 @@ func (c *AdapterChecker) Check(ctx context.Context) types.DiagnosticResult {
 					adapter.IPAddresses = append(adapter.IPAddresses, ipnet.IP.String())
 					mask := net.IP(ipnet.Mask).String()
 					adapter.SubnetMasks = append(adapter.SubnetMasks, mask)
+				} else {
+					adapter.IPv6 = append(adapter.IPv6, ipnet.String())
 				}
 			}
 		}
@@ -77,15 +84,69 @@ func (c *AdapterChecker) Check(ctx context.Context) types.DiagnosticResult {
 		adapters = append(adapters, adapter)
 	}
 
+	// 系统信息或路由表读取失败时只按名称和 MAC 地址分类，不标记默认路由
+	system, _ := c.system()
+	routes, _ := defaultRoutes(ctx, c.executor)
+	primary := classifyAdapters(adapters, system, routes)
+
+	activeCount := 0
+	for _, adapter := range adapters {
+		if !adapter.Virtual && adapter.Status == "Up" {
+			activeCount++
+		}
+	}
+
 	result.AddDetail("adapters", adapters)
 	result.AddDetail("totalCount", len(adapters))
 	result.AddDetail("activeCount", activeCount)
+	if primary != nil {
+		result.AddDetail("defaultRouteAdapter", primary.Name)
+	}
 
-	if activeCount == 0 {
-		result.SetWarning("未检测到活动的网络适配器", true)
-	} else {
-		result.SetOK("检测到 " + string(rune('0'+activeCount)) + " 个活动网络适配器")
+	switch {
+	case activeCount == 0:
+		result.SetWarning("未检测到活动的物理网卡", true)
+	case primary == nil:
+		result.SetOK(fmt.Sprintf("检测到 %d 个活动物理网卡，未找到默认路由", activeCount))
+	default:
+		result.SetOK(fmt.Sprintf("检测到 %d 个活动物理网卡，默认路由经由 %s", activeCount, primary.Name))
 	}
 
 	return *result
 }
+
+// classifyAdapters 用系统报告的网卡信息补充描述、类型和默认网关，返回承载默认路由的网卡
+// routes 为按有效跃点数排列的默认路由，第一条所在的网卡即实际承载流量的网卡
+func classifyAdapters(adapters []types.AdapterInfo, system []netif.Adapter, routes []netif.Route) *types.AdapterInfo {
+	byIndex := make(map[int]netif.Adapter, len(system))
+	for _, info := range system {
+		byIndex[info.Index] = info
+	}
+
+	for i := range adapters {
+		adapter := &adapters[i]
+		info := byIndex[adapter.Index]
+		if adapter.Description == "" {
+			adapter.Description = info.Description
+		}
+		if len(adapter.Gateways) == 0 {
+			adapter.Gateways = info.Gateways
+		}
+		kind := netif.Classify(adapter.Name, adapter.Description, adapter.MACAddress, info.Type)
+		adapter.Kind = string(kind)
+		adapter.Virtual = !kind.Physical()
+	}
+
+	if len(routes) == 0 {
+		return nil
+	}
+	for i := range adapters {
+		for _, addr := range adapters[i].IPAddresses {
+			if addr == routes[0].InterfaceAddr {
+				adapters[i].DefaultRoute = true
+				return &adapters[i]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package diagnostic
+
+import (
+	"testing"
+
+	"network-rescue-toolkit/pkg/netif"
+	"network-rescue-toolkit/pkg/types"
+)
+
+func TestClassifyAdapters(t *testing.T) {
+	adapters := []types.AdapterInfo{
+		{Index: 4, Name: "以太网", MACAddress: "3c:52:82:11:22:33", Status: "Up", IPAddresses: []string{"192.168.1.20"}},
+		{Index: 7, Name: "WLAN", MACAddress: "a4:c3:f0:11:22:33", Status: "Up", IPAddresses: []string{"192.168.0.20"}},
+		{Index: 12, Name: "vEthernet (WSL)", MACAddress: "00:15:5d:01:02:03", Status: "Up"},
+		{Index: 15, Name: "以太网 3", Status: "Up"},
+	}
+	system := []netif.Adapter{
+		{Index: 4, Name: "以太网", Description: "Realtek PCIe GbE Family Controller", Type: 6, Up: true, Gateways: []string{"192.168.1.1"}},
+		{Index: 7, Name: "WLAN", Description: "Intel(R) Wi-Fi 6 AX201", Type: 71, Up: true, Gateways: []string{"192.168.0.1"}},
+		{Index: 12, Name: "vEthernet (WSL)", Type: 6, Up: true},
+		{Index: 15, Name: "以太网 3", Description: "WireGuard Tunnel", Type: 53, Up: true},
+	}
+	// 有效跃点数（路由 + 接口）决定实际使用的默认路由
+	routes := []netif.Route{
+		{InterfaceAddr: "192.168.0.20", Gateway: "192.168.0.1", Metric: 35},
+		{InterfaceAddr: "192.168.1.20", Gateway: "192.168.1.1", Metric: 281},
+	}
+
+	primary := classifyAdapters(adapters, system, routes)
+	if primary == nil || primary.Name != "WLAN" || !adapters[1].DefaultRoute || adapters[0].DefaultRoute {
+		t.Fatalf("有效跃点数最小的默认路由所在网卡应为 WLAN: %+v", primary)
+	}
+
+	wantKinds := []netif.Kind{netif.KindWired, netif.KindWireless, netif.KindVirtual, netif.KindVPN}
+	for i, kind := range wantKinds {
+		if adapters[i].Kind != string(kind) || adapters[i].Virtual == kind.Physical() {
+			t.Errorf("%s 的类型为 %s（虚拟: %v），期望 %s", adapters[i].Name, adapters[i].Kind, adapters[i].Virtual, kind)
+		}
+	}
+	if adapters[0].Description != "Realtek PCIe GbE Family Controller" || len(adapters[0].Gateways) != 1 {
+		t.Errorf("应补充系统报告的描述和网关: %+v", adapters[0])
+	}
+
+	if classifyAdapters(adapters, system, nil) != nil {
+		t.Error("没有默认路由时不应标记网卡")
+	}
+}
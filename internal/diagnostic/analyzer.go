@@ -0,0 +1,382 @@
+package diagnostic
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"network-rescue-toolkit/pkg/types"
+)
+
+// Rule 根因分析规则，不适用时返回 nil
+type Rule func(results ResultSet) *types.RootCause
+
+// ResultSet 按检查器 ID 索引的诊断结果
+type ResultSet map[string]types.DiagnosticResult
+
+// NewResultSet 从诊断结果列表创建索引
+func NewResultSet(results []types.DiagnosticResult) ResultSet {
+	set := make(ResultSet, len(results))
+	for _, r := range results {
+		set[r.ID] = r
+	}
+	return set
+}
+
+// Failed 检查项是否报告了错误
+func (s ResultSet) Failed(id string) bool {
+	r, ok := s[id]
+	return ok && r.Status == types.StatusError
+}
+
+// Problem 检查项是否报告了错误或警告
+func (s ResultSet) Problem(id string) bool {
+	r, ok := s[id]
+	return ok && (r.Status == types.StatusError || r.Status == types.StatusWarning)
+}
+
+// Passed 检查项是否正常
+func (s ResultSet) Passed(id string) bool {
+	r, ok := s[id]
+	return ok && r.Status == types.StatusOK
+}
+
+// Skipped 检查项是否因依赖失败而被跳过
+func (s ResultSet) Skipped(id string) bool {
+	r, ok := s[id]
+	return ok && r.Status == types.StatusSkipped
+}
+
+// Affected 检查项是否受到故障影响（报告了错误、警告，或因依赖失败被跳过）
+// 依赖关系下 DNS 失败时连通性检测不会运行，规则应把被跳过的检查项也算作受影响
+func (s ResultSet) Affected(id string) bool {
+	return s.Problem(id) || s.Skipped(id)
+}
+
+// Evidence 以"名称: 结论"的形式描述检查项结果
+func (s ResultSet) Evidence(id string) string {
+	r := s[id]
+	return r.Name + ": " + r.Message
+}
+
+// detailInt 读取整数详情（兼容 JSON 反序列化后的 float64）
+func (s ResultSet) detailInt(id, key string) (int, bool) {
+	switch v := s[id].Details[key].(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	}
+	return 0, false
+}
+
+// hasIssue 检查项的 issues 详情中是否包含指定问题（兼容 JSON 反序列化后的 []interface{}）
+func (s ResultSet) hasIssue(id, issue string) bool {
+	switch issues := s[id].Details["issues"].(type) {
+	case []string:
+		for _, v := range issues {
+			if v == issue {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, v := range issues {
+			if v == issue {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Analyzer 根因分析器，将多个检查项的结果关联到同一个根因
+type Analyzer struct {
+	rules []Rule
+}
+
+// NewAnalyzer 创建使用默认规则的根因分析器
+func NewAnalyzer() *Analyzer {
+	a := &Analyzer{}
+	a.AddRule(adapterDownRule)
+	a.AddRule(noAddressRule)
+	a.AddRule(gatewayRule)
+	a.AddRule(ispRule)
+	a.AddRule(proxyRule)
+	a.AddRule(dnsRule)
+	a.AddRule(stackRule)
+	a.AddRule(hostsRule)
+	a.AddRule(ipv6FallbackRule)
+	return a
+}
+
+// AddRule 添加分析规则
+func (a *Analyzer) AddRule(rule Rule) {
+	a.rules = append(a.rules, rule)
+}
+
+// Analyze 分析诊断结果，按可能性从高到低返回根因
+// 同一修复器只保留评分最高的根因
+func (a *Analyzer) Analyze(results []types.DiagnosticResult) []types.RootCause {
+	set := NewResultSet(results)
+
+	causes := make([]types.RootCause, 0)
+	byRepairer := make(map[string]int)
+	for _, rule := range a.rules {
+		cause := rule(set)
+		if cause == nil {
+			continue
+		}
+		// 规则累加评分时避免浮点误差影响可信程度的判断
+		cause.Score = math.Min(math.Round(cause.Score*100)/100, 1)
+		cause.Confidence = types.ConfidenceFromScore(cause.Score)
+
+		if i, exists := byRepairer[cause.RepairerID]; exists && cause.RepairerID != "" {
+			if causes[i].Score < cause.Score {
+				causes[i] = *cause
+			}
+			continue
+		}
+		byRepairer[cause.RepairerID] = len(causes)
+		causes = append(causes, *cause)
+	}
+
+	sort.SliceStable(causes, func(i, j int) bool {
+		return causes[i].Score > causes[j].Score
+	})
+	return causes
+}
+
+// adapterDownRule 没有可用的网卡时，其余检查项的失败都是连带结果
+func adapterDownRule(s ResultSet) *types.RootCause {
+	if !s.Problem("adapter") {
+		return nil
+	}
+	cause := &types.RootCause{
+		ID:          "adapter-down",
+		Title:       "网卡未连接或已禁用",
+		Description: "没有处于活动状态的网络适配器，IP、DNS 和连通性检测的失败都由此导致",
+		Score:       0.6,
+		Evidence:    []string{s.Evidence("adapter")},
+		RepairerID:  "adapter",
+	}
+	if s.Failed("adapter") {
+		// 未检测到网卡通常是驱动问题，重置网卡不一定有效
+		cause.Score = 0.5
+	}
+	for _, id := range []string{"ip", "dns", "connectivity"} {
+		if s.Affected(id) {
+			cause.Score += 0.1
+			cause.RelatedChecks = append(cause.RelatedChecks, id)
+		}
+	}
+	return cause
+}
+
+// noAddressRule 网卡正常但没有拿到 IP 地址（DHCP 失败）
+func noAddressRule(s ResultSet) *types.RootCause {
+	if !s.Failed("ip") || s.Problem("adapter") {
+		return nil
+	}
+	cause := &types.RootCause{
+		ID:          "no-address",
+		Title:       "未获取到 IP 地址",
+		Description: "网卡已连接但没有有效的 IP 地址，通常是 DHCP 未响应，重新获取 IP 可以解决",
+		Score:       0.7,
+		Evidence:    []string{s.Evidence("ip")},
+		RepairerID:  "ip",
+	}
+	if s.Passed("adapter") {
+		cause.Evidence = append(cause.Evidence, s.Evidence("adapter"))
+	}
+	for _, id := range []string{"dns", "connectivity"} {
+		if s.Affected(id) {
+			cause.Score += 0.1
+			cause.RelatedChecks = append(cause.RelatedChecks, id)
+		}
+	}
+	return cause
+}
+
+// gatewayRule 电脑到路由器不通或网关配置错误，问题在本地网络而不是运营商
+func gatewayRule(s ResultSet) *types.RootCause {
+	var cause *types.RootCause
+	switch {
+	case s.Failed("gateway"):
+		cause = &types.RootCause{
+			ID:          "gateway-down",
+			Title:       "路由器无响应",
+			Description: "电脑到默认网关（路由器）不通，问题在本地网络：请检查路由器电源、网线或 Wi-Fi 连接，也可尝试重置网卡",
+			Score:       0.6,
+			Evidence:    []string{s.Evidence("gateway")},
+			RepairerID:  "adapter",
+		}
+		if routes, ok := s.detailInt("gateway", "routeCount"); ok && routes == 0 {
+			cause.Title = "没有默认路由"
+			cause.Description = "系统中没有默认路由，通常是 DHCP 未下发网关，重新获取 IP 可以解决"
+			cause.RepairerID = "ip"
+		}
+	case s.hasIssue("gateway", IssueGatewayOutsideSubnet):
+		cause = &types.RootCause{
+			ID:          "gateway-subnet",
+			Title:       "默认网关配置错误",
+			Description: "默认网关不在网卡的子网内，数据包无法送达路由器，通常是手动设置的 IP 或网关有误",
+			Score:       0.6,
+			Evidence:    []string{s.Evidence("gateway")},
+			RepairerID:  "ip",
+		}
+	default:
+		return nil
+	}
+	for _, id := range []string{"dns", "connectivity"} {
+		if s.Affected(id) {
+			cause.Score += 0.1
+			cause.RelatedChecks = append(cause.RelatedChecks, id)
+		}
+	}
+	return cause
+}
+
+// ispRule 路由器响应正常、未启用代理，但无法访问互联网，问题在路由器之外
+// 运营商故障时 DNS 通常同样失败，连通性检测会因此被跳过
+func ispRule(s ResultSet) *types.RootCause {
+	if !s.Passed("gateway") || s.Problem("proxy") {
+		return nil
+	}
+	if !s.Failed("connectivity") && !(s.Skipped("connectivity") && s.Failed("dns")) {
+		return nil
+	}
+	cause := &types.RootCause{
+		ID:            "isp",
+		Title:         "宽带或运营商网络故障",
+		Description:   "电脑到路由器正常，但无法访问互联网，问题在路由器之外：请检查光猫和路由器的宽带拨号状态，或联系运营商",
+		Score:         0.6,
+		Evidence:      []string{s.Evidence("gateway"), s.Evidence("connectivity")},
+		RelatedChecks: []string{"connectivity"},
+	}
+	if s.Failed("dns") {
+		// DNS 同样失败说明连运营商的 DNS 都不可达
+		cause.Score += 0.1
+		cause.RelatedChecks = append(cause.RelatedChecks, "dns")
+	}
+	return cause
+}
+
+// proxyRule 启用了代理且网页访问失败，代理很可能就是原因
+func proxyRule(s ResultSet) *types.RootCause {
+	if !s.Problem("proxy") {
+		return nil
+	}
+	cause := &types.RootCause{
+		ID:          "proxy",
+		Title:       "系统代理配置异常",
+		Description: "系统启用了代理服务器，代理程序未运行或不可用时浏览器将无法打开网页",
+		Score:       0.3, // 单独启用代理可能是用户有意为之
+		Evidence:    []string{s.Evidence("proxy")},
+		RepairerID:  "proxy",
+	}
+	if s.Passed("ip") {
+		cause.Score += 0.1
+	}
+	for _, id := range []string{"connectivity", "dns"} {
+		if s.Affected(id) {
+			cause.Score += 0.25
+			cause.Evidence = append(cause.Evidence, s.Evidence(id))
+			cause.RelatedChecks = append(cause.RelatedChecks, id)
+		}
+	}
+	return cause
+}
+
+// dnsRule DNS 解析失败或缓慢；按 IP 地址仍能连通时可信度更高
+func dnsRule(s ResultSet) *types.RootCause {
+	if !s.Problem("dns") {
+		return nil
+	}
+	cause := &types.RootCause{
+		ID:          "dns",
+		Title:       "DNS 服务异常",
+		Description: "域名解析失败或缓慢，更换 DNS 服务器或刷新缓存可以解决",
+		Score:       0.6,
+		Evidence:    []string{s.Evidence("dns")},
+		RepairerID:  "dns",
+	}
+
+	reachable, _ := s.detailInt("connectivity", "successCount")
+	switch {
+	case reachable > 0:
+		cause.Description = "网络本身可以连通，但域名解析失败或缓慢，更换 DNS 服务器或刷新缓存可以解决"
+		cause.Evidence = append(cause.Evidence, fmt.Sprintf("%s（%d 个目标可达）", s.Evidence("connectivity"), reachable))
+		if s.Failed("dns") {
+			cause.Score += 0.2
+		}
+		if s.Problem("connectivity") {
+			cause.Score += 0.1
+			cause.RelatedChecks = append(cause.RelatedChecks, "connectivity")
+		}
+	case s.Skipped("connectivity"):
+		// DNS 失败时连通性检测被跳过，无法确认网络本身是否可达
+		cause.Score = 0.5
+		cause.RelatedChecks = append(cause.RelatedChecks, "connectivity")
+	default:
+		return nil
+	}
+	return cause
+}
+
+// stackRule 网卡和 IP 正常、未启用代理，但 DNS 和连通性全部失败，怀疑网络协议栈损坏
+func stackRule(s ResultSet) *types.RootCause {
+	if !s.Passed("adapter") || !s.Passed("ip") || s.Problem("proxy") {
+		return nil
+	}
+	// DNS 失败时连通性检测被跳过，同样视为无法建立连接
+	if !s.Failed("dns") || !(s.Failed("connectivity") || s.Skipped("connectivity")) {
+		return nil
+	}
+	return &types.RootCause{
+		ID:            "winsock",
+		Title:         "网络协议栈异常",
+		Description:   "网卡和 IP 配置正常，但无法解析域名也无法建立连接，可能是 Winsock 被第三方软件修改",
+		Score:         0.55,
+		Evidence:      []string{s.Evidence("adapter"), s.Evidence("ip"), s.Evidence("dns"), s.Evidence("connectivity")},
+		RelatedChecks: []string{"dns", "connectivity"},
+		RepairerID:    "winsock",
+	}
+}
+
+// hostsRule HOSTS 文件中存在可疑条目
+func hostsRule(s ResultSet) *types.RootCause {
+	suspicious, _ := s.detailInt("hosts", "suspiciousCount")
+	if !s.Problem("hosts") || suspicious == 0 {
+		return nil
+	}
+	cause := &types.RootCause{
+		ID:          "hosts",
+		Title:       "HOSTS 文件被篡改",
+		Description: "HOSTS 文件中存在可疑条目，部分网站可能被劫持或无法访问",
+		Score:       0.5,
+		Evidence:    []string{fmt.Sprintf("%s（%d 个可疑条目）", s.Evidence("hosts"), suspicious)},
+		RepairerID:  "hosts",
+	}
+	if s.Passed("dns") && s.Passed("connectivity") {
+		// 整体网络正常时只影响个别网站
+		cause.Score = 0.4
+	}
+	return cause
+}
+
+// ipv6FallbackRule IPv6 故障导致访问需等待超时回退
+func ipv6FallbackRule(s ResultSet) *types.RootCause {
+	if s["ipv6"].Details["issue"] != IssueIPv6SlowFallback {
+		return nil
+	}
+	return &types.RootCause{
+		ID:          "ipv6-fallback",
+		Title:       "IPv6 故障导致访问缓慢",
+		Description: "IPv6 已启用但无法连通，每次访问都需等待 IPv6 超时后才回退到 IPv4",
+		Score:       0.8,
+		Evidence:    []string{s.Evidence("ipv6")},
+		RepairerID:  "ipv6",
+	}
+}
@@ -0,0 +1,172 @@
+package diagnostic
+
+import (
+	"context"
+	"testing"
+
+	"network-rescue-toolkit/pkg/types"
+)
+
+// result 构造指定状态的诊断结果
+func result(id string, status types.DiagnosticStatus, details map[string]any) types.DiagnosticResult {
+	r := types.NewDiagnosticResult(id, id+"-检查")
+	r.Status = status
+	r.Message = string(status)
+	for k, v := range details {
+		r.AddDetail(k, v)
+	}
+	return *r
+}
+
+func TestAnalyzeProxyExplainsDNSAndConnectivity(t *testing.T) {
+	results := []types.DiagnosticResult{
+		result("adapter", types.StatusOK, nil),
+		result("ip", types.StatusOK, nil),
+		result("dns", types.StatusError, map[string]any{"successCount": 0}),
+		result("hosts", types.StatusOK, map[string]any{"suspiciousCount": 0}),
+		result("proxy", types.StatusWarning, nil),
+		result("connectivity", types.StatusSkipped, nil), // DNS 失败时连通性检测被跳过
+	}
+
+	causes := NewAnalyzer().Analyze(results)
+	if len(causes) != 2 || causes[1].RepairerID != "dns" {
+		t.Fatalf("期望代理和 DNS 2 个根因，实际 %+v", causes)
+	}
+	top := causes[0]
+	if top.RepairerID != "proxy" || top.Confidence != types.ConfidenceHigh {
+		t.Errorf("代理应为高可信度的首要根因: %+v", top)
+	}
+	if len(top.Evidence) != 3 || len(top.RelatedChecks) != 2 {
+		t.Errorf("应关联 DNS 和连通性的失败: %+v", top)
+	}
+}
+
+func TestAnalyzeDNSWithReachableNetwork(t *testing.T) {
+	results := []types.DiagnosticResult{
+		result("adapter", types.StatusOK, nil),
+		result("ip", types.StatusOK, nil),
+		result("dns", types.StatusWarning, nil), // 部分域名解析失败时连通性检测照常运行
+		result("proxy", types.StatusOK, nil),
+		result("connectivity", types.StatusWarning, map[string]any{"successCount": 2.0}),
+	}
+
+	causes := NewAnalyzer().Analyze(results)
+	if len(causes) != 1 || causes[0].RepairerID != "dns" || causes[0].Confidence != types.ConfidenceMedium || len(causes[0].Evidence) != 2 {
+		t.Errorf("按 IP 可连通时应判断为 DNS 问题: %+v", causes)
+	}
+}
+
+func TestAnalyzeDNSFailureSkipsConnectivity(t *testing.T) {
+	results := []types.DiagnosticResult{
+		result("adapter", types.StatusOK, nil),
+		result("ip", types.StatusOK, nil),
+		result("dns", types.StatusError, nil),
+		result("proxy", types.StatusOK, nil),
+		result("connectivity", types.StatusSkipped, nil),
+	}
+
+	causes := NewAnalyzer().Analyze(results)
+	if len(causes) != 2 || causes[0].RepairerID != "winsock" || causes[1].RepairerID != "dns" {
+		t.Errorf("DNS 失败导致连通性被跳过时应推断协议栈和 DNS 问题: %+v", causes)
+	}
+}
+
+func TestAnalyzeRanksCauses(t *testing.T) {
+	results := []types.DiagnosticResult{
+		result("adapter", types.StatusOK, nil),
+		result("ip", types.StatusError, nil),
+		result("dns", types.StatusSkipped, nil),
+		result("hosts", types.StatusWarning, map[string]any{"suspiciousCount": 3}),
+		result("proxy", types.StatusOK, nil),
+		result("connectivity", types.StatusSkipped, nil),
+	}
+
+	causes := NewAnalyzer().Analyze(results)
+	if len(causes) != 2 {
+		t.Fatalf("期望 2 个根因，实际 %+v", causes)
+	}
+	if causes[0].RepairerID != "ip" || causes[0].Confidence != types.ConfidenceHigh {
+		t.Errorf("未获取到 IP 应排在首位: %+v", causes[0])
+	}
+	if causes[1].RepairerID != "hosts" || causes[1].Confidence != types.ConfidenceMedium {
+		t.Errorf("HOSTS 篡改应排在其后: %+v", causes[1])
+	}
+}
+
+func TestAnalyzeHealthyNetwork(t *testing.T) {
+	results := []types.DiagnosticResult{
+		result("adapter", types.StatusOK, nil),
+		result("ip", types.StatusOK, nil),
+		result("dns", types.StatusOK, nil),
+		result("connectivity", types.StatusOK, map[string]any{"successCount": 5}),
+	}
+
+	if causes := NewAnalyzer().Analyze(results); len(causes) != 0 {
+		t.Errorf("网络正常时不应推断出根因: %+v", causes)
+	}
+}
+
+func TestAnalyzeSeparatesRouterFromISP(t *testing.T) {
+	router := []types.DiagnosticResult{
+		result("adapter", types.StatusOK, nil),
+		result("ip", types.StatusOK, nil),
+		result("gateway", types.StatusError, map[string]any{"routeCount": 1}),
+		result("dns", types.StatusError, nil),
+		result("proxy", types.StatusOK, nil),
+		result("connectivity", types.StatusSkipped, nil),
+	}
+	causes := NewAnalyzer().Analyze(router)
+	if len(causes) == 0 || causes[0].ID != "gateway-down" || causes[0].RepairerID != "adapter" {
+		t.Errorf("网关不通时首要根因应为路由器无响应: %+v", causes)
+	}
+
+	isp := []types.DiagnosticResult{
+		result("adapter", types.StatusOK, nil),
+		result("ip", types.StatusOK, nil),
+		result("gateway", types.StatusOK, nil),
+		result("dns", types.StatusError, nil),
+		result("proxy", types.StatusOK, nil),
+		result("connectivity", types.StatusSkipped, nil),
+	}
+	causes = NewAnalyzer().Analyze(isp)
+	if len(causes) == 0 || causes[0].ID != "isp" || causes[0].RepairerID != "" {
+		t.Errorf("网关正常但无法上网时首要根因应为运营商故障: %+v", causes)
+	}
+}
+
+func TestAnalyzeGatewayOutsideSubnet(t *testing.T) {
+	results := []types.DiagnosticResult{
+		result("adapter", types.StatusOK, nil),
+		result("ip", types.StatusOK, nil),
+		result("gateway", types.StatusWarning, map[string]any{"issues": []any{IssueGatewayOutsideSubnet}}),
+		result("connectivity", types.StatusOK, nil),
+	}
+	causes := NewAnalyzer().Analyze(results)
+	if len(causes) != 1 || causes[0].ID != "gateway-subnet" || causes[0].RepairerID != "ip" {
+		t.Errorf("网关不在子网内时应建议重新获取 IP: %+v", causes)
+	}
+}
+
+func TestAnalyzeEngineResultsForISPOutage(t *testing.T) {
+	// 使用真实检查器声明的依赖关系运行引擎：网关正常，DNS 失败，连通性检测被跳过
+	e := newEmptyEngine()
+	e.RegisterChecker(&stubChecker{id: "adapter", status: types.StatusOK})
+	e.RegisterChecker(&stubChecker{id: "ip", status: types.StatusOK, deps: NewIPChecker(nil).DependsOn()})
+	e.RegisterChecker(&stubChecker{id: "gateway", status: types.StatusOK, deps: NewGatewayChecker(nil).DependsOn()})
+	e.RegisterChecker(&stubChecker{id: "dns", status: types.StatusError, deps: NewDNSChecker().DependsOn()})
+	e.RegisterChecker(&stubChecker{id: "proxy", status: types.StatusOK})
+	e.RegisterChecker(&stubChecker{id: "connectivity", status: types.StatusError, deps: NewConnectivityChecker().DependsOn()})
+
+	results := e.RunAll(context.Background())
+	if connectivity := NewResultSet(results)["connectivity"]; connectivity.Status != types.StatusSkipped {
+		t.Fatalf("DNS 失败时连通性检测应被跳过: %+v", connectivity)
+	}
+
+	causes := NewAnalyzer().Analyze(results)
+	if len(causes) == 0 || causes[0].ID != "isp" {
+		t.Fatalf("网关正常但 DNS 和连通性失败时首要根因应为运营商故障: %+v", causes)
+	}
+	if len(causes[0].RelatedChecks) != 2 || causes[0].Confidence != types.ConfidenceMedium {
+		t.Errorf("运营商故障应关联 DNS 和连通性: %+v", causes[0])
+	}
+}
@@ -2,8 +2,12 @@ package diagnostic
 
 import (
 	"context"
+	"strings"
 	"sync"
 
+	"network-rescue-toolkit/pkg/audit"
+	"network-rescue-toolkit/pkg/executor"
+	"network-rescue-toolkit/pkg/registry"
 	"network-rescue-toolkit/pkg/types"
 )
 
@@ -17,32 +21,74 @@ type Checker interface {
 	Check(ctx context.Context) types.DiagnosticResult
 }
 
+// DependentChecker 声明依赖的检查器（可选接口）
+// 依赖的检查项失败时，引擎直接将其标记为跳过，不再等待超时
+type DependentChecker interface {
+	// DependsOn 返回依赖的检查器 ID
+	DependsOn() []string
+}
+
+// DefaultConcurrency 默认同时运行的检查器数量
+const DefaultConcurrency = 4
+
 // Engine 诊断引擎
 type Engine struct {
-	checkers []Checker
-	results  []types.DiagnosticResult
-	mu       sync.RWMutex
+	checkers    []Checker
+	results     []types.DiagnosticResult
+	concurrency int
+	onEvent     EventHandler
+	mu          sync.RWMutex
 }
 
 // NewEngine 创建新的诊断引擎
 func NewEngine() *Engine {
-	e := &Engine{
-		checkers: make([]Checker, 0),
-		results:  make([]types.DiagnosticResult, 0),
-	}
+	return NewEngineWithRunner(executor.NewCommandExecutor())
+}
+
+// NewEngineWithRunner 使用指定的命令执行器创建诊断引擎
+func NewEngineWithRunner(runner executor.Runner) *Engine {
+	e := newEmptyEngine()
 	// 注册所有检查器
-	e.registerDefaultCheckers()
+	e.registerDefaultCheckers(runner)
 	return e
 }
 
+// newEmptyEngine 创建未注册任何检查器的诊断引擎
+func newEmptyEngine() *Engine {
+	return &Engine{
+		checkers:    make([]Checker, 0),
+		results:     make([]types.DiagnosticResult, 0),
+		concurrency: DefaultConcurrency,
+	}
+}
+
 // registerDefaultCheckers 注册默认检查器
-func (e *Engine) registerDefaultCheckers() {
-	e.RegisterChecker(NewAdapterChecker())
-	e.RegisterChecker(NewIPChecker())
+func (e *Engine) registerDefaultCheckers(runner executor.Runner) {
+	e.RegisterChecker(NewAdapterChecker(runner))
+	e.RegisterChecker(NewIPChecker(runner))
+	e.RegisterChecker(NewGatewayChecker(runner))
 	e.RegisterChecker(NewDNSChecker())
 	e.RegisterChecker(NewHostsChecker())
-	e.RegisterChecker(NewProxyChecker())
+	e.RegisterChecker(NewProxyChecker(registry.NewDefaultStore()))
 	e.RegisterChecker(NewConnectivityChecker())
+	e.RegisterChecker(NewIPv6Checker(runner))
+}
+
+// SetConcurrency 设置同时运行的检查器数量（1 表示逐个运行）
+func (e *Engine) SetConcurrency(n int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if n < 1 {
+		n = 1
+	}
+	e.concurrency = n
+}
+
+// OnEvent 设置诊断事件回调，检查器开始、报告阶段性结果和完成时调用
+func (e *Engine) OnEvent(handler EventHandler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.onEvent = handler
 }
 
 // RegisterChecker 注册检查器
@@ -53,31 +99,246 @@ func (e *Engine) RegisterChecker(c Checker) {
 }
 
 // RunAll 运行所有检查器
+// 无依赖关系的检查器并发执行；依赖的检查项失败时直接标记为跳过。结果按注册顺序返回
 func (e *Engine) RunAll(ctx context.Context) []types.DiagnosticResult {
 	e.mu.Lock()
 	e.results = make([]types.DiagnosticResult, 0, len(e.checkers))
+	checkers := append([]Checker(nil), e.checkers...)
+	limit := e.concurrency
+	emitter := &eventEmitter{handler: e.onEvent, total: len(checkers)}
 	e.mu.Unlock()
 
-	for _, checker := range e.checkers {
-		select {
-		case <-ctx.Done():
-			return e.results
-		default:
-			result := checker.Check(ctx)
-			e.mu.Lock()
-			e.results = append(e.results, result)
-			e.mu.Unlock()
+	runs := e.schedule(ctx, checkers, limit, emitter)
+
+	results := make([]types.DiagnosticResult, 0, len(runs))
+	for _, run := range runs {
+		// 被取消的检查器没有结果
+		if run.finished {
+			results = append(results, run.result)
 		}
 	}
 
-	return e.results
+	e.mu.Lock()
+	e.results = results
+	e.mu.Unlock()
+	return results
+}
+
+// checkerRun 单个检查器的运行状态
+type checkerRun struct {
+	checker  Checker
+	deps     []int
+	done     chan struct{}
+	result   types.DiagnosticResult
+	finished bool
+}
+
+// failed 检查项是否失败（失败或被跳过的检查项会使依赖它的检查项跳过）
+func (r *checkerRun) failed() bool {
+	return r.finished && (r.result.Status == types.StatusError || r.result.Status == types.StatusSkipped)
+}
+
+// schedule 按依赖关系并发运行检查器，最多同时运行 limit 个
+func (e *Engine) schedule(ctx context.Context, checkers []Checker, limit int, emitter *eventEmitter) []*checkerRun {
+	runs := make([]*checkerRun, len(checkers))
+	index := make(map[string]int, len(checkers))
+	for i, c := range checkers {
+		runs[i] = &checkerRun{checker: c, done: make(chan struct{})}
+		if _, exists := index[c.ID()]; !exists {
+			index[c.ID()] = i
+		}
+	}
+	for i, c := range checkers {
+		for _, dep := range dependenciesOf(c) {
+			// 未注册的依赖直接忽略
+			if j, ok := index[dep]; ok && j != i {
+				runs[i].deps = append(runs[i].deps, j)
+			}
+		}
+	}
+	cyclic := findCycles(runs)
+
+	if limit <= 0 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+
+	var wg sync.WaitGroup
+	for i, run := range runs {
+		wg.Add(1)
+		go func(i int, run *checkerRun) {
+			defer wg.Done()
+			defer close(run.done)
+
+			if cyclic[i] {
+				run.result = *types.NewDiagnosticResult(run.checker.ID(), run.checker.Name())
+				run.result.SetError("检查项之间存在循环依赖", false)
+				run.finished = true
+				emitter.emit(EventFinished, run.checker, &run.result)
+				return
+			}
+
+			var failedDeps []*checkerRun
+			for _, j := range run.deps {
+				select {
+				case <-runs[j].done:
+				case <-ctx.Done():
+					return
+				}
+				if !runs[j].finished {
+					// 依赖被取消，本项同样取消
+					return
+				}
+				if runs[j].failed() {
+					failedDeps = append(failedDeps, runs[j])
+				}
+			}
+			if len(failedDeps) > 0 {
+				run.result = skippedResult(run.checker, failedDeps)
+				run.finished = true
+				emitter.emit(EventFinished, run.checker, &run.result)
+				return
+			}
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				return
+			}
+
+			run.result = runChecker(ctx, run.checker, emitter)
+			run.finished = true
+		}(i, run)
+	}
+	wg.Wait()
+
+	return runs
+}
+
+// runChecker 运行单个检查器并发送开始、阶段性结果和完成事件
+func runChecker(ctx context.Context, c Checker, emitter *eventEmitter) types.DiagnosticResult {
+	emitter.emit(EventStarted, c, nil)
+
+	ctx = audit.WithComponent(ctx, "diagnostic/"+c.ID())
+	ctx = withProgress(ctx, func(partial types.DiagnosticResult) {
+		emitter.emit(EventProgress, c, &partial)
+	})
+	result := c.Check(ctx)
+
+	emitter.emit(EventFinished, c, &result)
+	return result
+}
+
+// dependenciesOf 返回检查器声明的依赖
+func dependenciesOf(c Checker) []string {
+	if dependent, ok := c.(DependentChecker); ok {
+		return dependent.DependsOn()
+	}
+	return nil
+}
+
+// findCycles 找出处于循环依赖中的检查器
+// Kahn 拓扑排序后剩余的节点中，只有能沿依赖回到自身的才在环上；
+// 其余只是依赖了环上的检查项，按依赖失败处理为跳过
+func findCycles(runs []*checkerRun) map[int]bool {
+	inDegree := make([]int, len(runs))
+	dependents := make([][]int, len(runs))
+	for i, run := range runs {
+		inDegree[i] = len(run.deps)
+		for _, j := range run.deps {
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	queue := make([]int, 0, len(runs))
+	for i, degree := range inDegree {
+		if degree == 0 {
+			queue = append(queue, i)
+		}
+	}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for _, k := range dependents[i] {
+			inDegree[k]--
+			if inDegree[k] == 0 {
+				queue = append(queue, k)
+			}
+		}
+	}
+
+	remaining := make(map[int]bool)
+	for i, degree := range inDegree {
+		if degree > 0 {
+			remaining[i] = true
+		}
+	}
+	cyclic := make(map[int]bool)
+	for i := range remaining {
+		if reaches(runs, remaining, i, i) {
+			cyclic[i] = true
+		}
+	}
+	return cyclic
+}
+
+// reaches 判断在 nodes 范围内能否从 from 沿依赖到达 to
+func reaches(runs []*checkerRun, nodes map[int]bool, from, to int) bool {
+	visited := make(map[int]bool)
+	stack := append([]int(nil), runs[from].deps...)
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if i == to {
+			return true
+		}
+		if visited[i] || !nodes[i] {
+			continue
+		}
+		visited[i] = true
+		stack = append(stack, runs[i].deps...)
+	}
+	return false
+}
+
+// skippedResult 创建因依赖失败而跳过的结果
+func skippedResult(c Checker, failedDeps []*checkerRun) types.DiagnosticResult {
+	result := types.NewDiagnosticResult(c.ID(), c.Name())
+
+	names := make([]string, 0, len(failedDeps))
+	ids := make([]string, 0, len(failedDeps))
+	for _, dep := range failedDeps {
+		names = append(names, dep.result.Name)
+		ids = append(ids, dep.result.ID)
+	}
+	result.AddDetail("skippedBecause", ids)
+	result.SetSkipped("已跳过：" + strings.Join(names, "、") + " 未通过")
+	return *result
 }
 
 // RunSingle 运行单个检查器
 func (e *Engine) RunSingle(ctx context.Context, id string) types.DiagnosticResult {
+	e.mu.RLock()
+	handler := e.onEvent
+	e.mu.RUnlock()
+
+	return e.runSingle(ctx, id, handler)
+}
+
+// Recheck 重新运行单个检查器但不发送诊断事件，用于修复后验证，不打断前端的诊断进度
+func (e *Engine) Recheck(ctx context.Context, id string) types.DiagnosticResult {
+	return e.runSingle(ctx, id, nil)
+}
+
+// runSingle 运行单个检查器，事件发送给 handler（可为 nil）
+func (e *Engine) runSingle(ctx context.Context, id string, handler EventHandler) types.DiagnosticResult {
 	for _, checker := range e.checkers {
 		if checker.ID() == id {
-			return checker.Check(ctx)
+			return runChecker(ctx, checker, &eventEmitter{handler: handler, total: 1})
 		}
 	}
 
@@ -0,0 +1,267 @@
+package diagnostic
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"network-rescue-toolkit/pkg/executor"
+	"network-rescue-toolkit/pkg/registry"
+	"network-rescue-toolkit/pkg/types"
+)
+
+// sampleIPConfig 中文系统的 ipconfig /all 输出样本
+const sampleIPConfig = `
+Windows IP 配置
+
+以太网适配器 以太网:
+
+   DHCP 已启用 . . . . . . . . . . . : 是
+   IPv4 地址 . . . . . . . . . . . . : 192.168.1.20(首选)
+   子网掩码  . . . . . . . . . . . . : 255.255.255.0
+   默认网关. . . . . . . . . . . . . : 192.168.1.1
+   DNS 服务器  . . . . . . . . . . . : 192.168.1.1
+`
+
+func TestEngineRunAllFlow(t *testing.T) {
+	fake := executor.NewFakeRunner().OnOutput("ipconfig /all", sampleIPConfig)
+	store := registry.NewMemoryStore()
+	store.SetDWORD(registry.ProxySettingsPath, "ProxyEnable", 1)
+	store.SetString(registry.ProxySettingsPath, "ProxyServer", "127.0.0.1:7890")
+
+	e := newEmptyEngine()
+	e.RegisterChecker(NewIPChecker(fake))
+	e.RegisterChecker(NewProxyChecker(store))
+
+	results := e.RunAll(context.Background())
+	if len(results) != 2 {
+		t.Fatalf("期望 2 个诊断结果，实际 %d", len(results))
+	}
+
+	ip := results[0]
+	if ip.ID != "ip" || ip.Status != types.StatusOK || ip.Message != "IP 配置正常 (DHCP)" {
+		t.Errorf("IP 诊断结果不符: %+v", ip)
+	}
+
+	proxy := results[1]
+	if proxy.ID != "proxy" || proxy.Status != types.StatusWarning || !proxy.Repairable {
+		t.Errorf("代理诊断结果不符: %+v", proxy)
+	}
+
+	if got := e.GetResults(); len(got) != 2 {
+		t.Errorf("GetResults 应返回最近一次结果，实际 %d 个", len(got))
+	}
+}
+
+func TestIPCheckerCommandFailure(t *testing.T) {
+	fake := executor.NewFakeRunner().OnFailure("ipconfig /all", 1, "拒绝访问")
+
+	result := NewIPChecker(fake).Check(context.Background())
+	if result.Status != types.StatusError || result.Repairable {
+		t.Errorf("ipconfig 失败时应报告不可修复的错误: %+v", result)
+	}
+}
+
+func TestIPCheckerNoAddress(t *testing.T) {
+	output := `
+以太网适配器 以太网:
+
+   媒体状态  . . . . . . . . . . . . : 媒体已断开连接
+   DHCP 已启用 . . . . . . . . . . . : 是
+`
+	fake := executor.NewFakeRunner().OnOutput("ipconfig /all", output)
+
+	result := NewIPChecker(fake).Check(context.Background())
+	if result.Status != types.StatusError || !result.Repairable {
+		t.Errorf("没有 IP 地址时应报告可修复的错误: %+v", result)
+	}
+}
+
+func TestEngineRunSingleUnknown(t *testing.T) {
+	e := newEmptyEngine()
+	if result := e.RunSingle(context.Background(), "missing"); result.Status != types.StatusError {
+		t.Errorf("未知检查项应返回错误: %+v", result)
+	}
+}
+
+func TestEngineRecheckEmitsNoEvents(t *testing.T) {
+	e := newEmptyEngine()
+	e.RegisterChecker(&progressChecker{stubChecker{id: "proxy", status: types.StatusWarning}})
+
+	var events []Event
+	e.OnEvent(func(event Event) { events = append(events, event) })
+
+	if result := e.Recheck(context.Background(), "proxy"); result.Status != types.StatusWarning {
+		t.Errorf("重新检查结果不符: %+v", result)
+	}
+	if len(events) != 0 {
+		t.Errorf("修复后验证不应发送诊断进度事件: %+v", events)
+	}
+
+	e.RunSingle(context.Background(), "proxy")
+	if len(events) == 0 {
+		t.Error("手动运行单个诊断项时应发送事件")
+	}
+}
+
+// stubChecker 返回固定状态的检查器，可声明依赖并模拟耗时
+type stubChecker struct {
+	id      string
+	status  types.DiagnosticStatus
+	deps    []string
+	delay   time.Duration
+	running *int32
+	peak    *int32
+}
+
+func (c *stubChecker) ID() string          { return c.id }
+func (c *stubChecker) Name() string        { return c.id + "-检查" }
+func (c *stubChecker) DependsOn() []string { return c.deps }
+
+func (c *stubChecker) Check(ctx context.Context) types.DiagnosticResult {
+	if c.running != nil {
+		n := atomic.AddInt32(c.running, 1)
+		for {
+			peak := atomic.LoadInt32(c.peak)
+			if n <= peak || atomic.CompareAndSwapInt32(c.peak, peak, n) {
+				break
+			}
+		}
+		defer atomic.AddInt32(c.running, -1)
+	}
+	time.Sleep(c.delay)
+
+	result := types.NewDiagnosticResult(c.id, c.Name())
+	result.Status = c.status
+	return *result
+}
+
+func TestEngineSkipsDependentsOfFailedChecker(t *testing.T) {
+	e := newEmptyEngine()
+	e.RegisterChecker(&stubChecker{id: "adapter", status: types.StatusError})
+	e.RegisterChecker(&stubChecker{id: "ip", status: types.StatusOK, deps: []string{"adapter"}})
+	e.RegisterChecker(&stubChecker{id: "dns", status: types.StatusOK, deps: []string{"ip"}})
+	e.RegisterChecker(&stubChecker{id: "proxy", status: types.StatusWarning})
+	e.RegisterChecker(&stubChecker{id: "connectivity", status: types.StatusOK, deps: []string{"adapter", "dns", "missing"}})
+
+	results := e.RunAll(context.Background())
+	want := []types.DiagnosticStatus{types.StatusError, types.StatusSkipped, types.StatusSkipped, types.StatusWarning, types.StatusSkipped}
+	if len(results) != len(want) {
+		t.Fatalf("期望 %d 个结果，实际 %d", len(want), len(results))
+	}
+	for i, status := range want {
+		if results[i].Status != status {
+			t.Errorf("%s 状态为 %s，期望 %s", results[i].ID, results[i].Status, status)
+		}
+	}
+
+	skipped := results[4].Details["skippedBecause"].([]string)
+	if len(skipped) != 2 || skipped[0] != "adapter" || skipped[1] != "dns" {
+		t.Errorf("应记录失败的依赖项: %v", skipped)
+	}
+	if results[1].Message != "已跳过：adapter-检查 未通过" {
+		t.Errorf("跳过原因不符: %s", results[1].Message)
+	}
+}
+
+func TestEngineRunsIndependentCheckersConcurrently(t *testing.T) {
+	var running, peak int32
+	e := newEmptyEngine()
+	e.SetConcurrency(2)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		e.RegisterChecker(&stubChecker{id: id, status: types.StatusOK, delay: 50 * time.Millisecond, running: &running, peak: &peak})
+	}
+
+	start := time.Now()
+	results := e.RunAll(context.Background())
+	elapsed := time.Since(start)
+
+	if len(results) != 4 || results[0].ID != "a" || results[3].ID != "d" {
+		t.Errorf("结果应按注册顺序返回: %+v", results)
+	}
+	if peak != 2 {
+		t.Errorf("同时运行的检查器数量应为 2，实际 %d", peak)
+	}
+	if elapsed >= 190*time.Millisecond {
+		t.Errorf("独立检查器应并发执行，耗时 %v", elapsed)
+	}
+}
+
+func TestEngineDependencyCycle(t *testing.T) {
+	e := newEmptyEngine()
+	e.RegisterChecker(&stubChecker{id: "a", status: types.StatusOK, deps: []string{"b"}})
+	e.RegisterChecker(&stubChecker{id: "b", status: types.StatusOK, deps: []string{"a"}})
+	e.RegisterChecker(&stubChecker{id: "c", status: types.StatusOK})
+	e.RegisterChecker(&stubChecker{id: "d", status: types.StatusOK, deps: []string{"b"}})
+	e.RegisterChecker(&stubChecker{id: "e", status: types.StatusOK, deps: []string{"d"}})
+
+	results := e.RunAll(context.Background())
+	if len(results) != 5 || results[0].Status != types.StatusError || results[1].Status != types.StatusError || results[2].Status != types.StatusOK {
+		t.Fatalf("循环依赖的检查项应报告错误且不影响其他检查项: %+v", results)
+	}
+	for _, i := range []int{3, 4} {
+		if results[i].Status != types.StatusSkipped || results[i].Message == "检查项之间存在循环依赖" {
+			t.Errorf("只依赖循环的检查项应跳过而不是报告循环依赖: %+v", results[i])
+		}
+	}
+	if skipped := results[3].Details["skippedBecause"].([]string); len(skipped) != 1 || skipped[0] != "b" {
+		t.Errorf("应记录处于循环中的依赖项: %v", skipped)
+	}
+}
+
+// progressChecker 运行中报告一次阶段性结果的检查器
+type progressChecker struct{ stubChecker }
+
+func (c *progressChecker) Check(ctx context.Context) types.DiagnosticResult {
+	partial := types.NewDiagnosticResult(c.id, c.Name())
+	partial.Message = "进行中"
+	ReportProgress(ctx, *partial)
+	return c.stubChecker.Check(ctx)
+}
+
+func TestEngineEmitsEvents(t *testing.T) {
+	e := newEmptyEngine()
+	e.SetConcurrency(1)
+	e.RegisterChecker(&stubChecker{id: "adapter", status: types.StatusError})
+	e.RegisterChecker(&stubChecker{id: "ip", status: types.StatusOK, deps: []string{"adapter"}})
+	e.RegisterChecker(&progressChecker{stubChecker{id: "proxy", status: types.StatusOK}})
+
+	var events []Event
+	e.OnEvent(func(event Event) { events = append(events, event) })
+	e.RunAll(context.Background())
+
+	byChecker := make(map[string][]EventType)
+	finished := 0
+	for _, event := range events {
+		byChecker[event.CheckerID] = append(byChecker[event.CheckerID], event.Type)
+		if event.Total != 3 {
+			t.Errorf("事件的总数应为 3: %+v", event)
+		}
+		if event.Type == EventFinished {
+			finished++
+			if event.Completed != finished || event.Result == nil || event.Result.ID != event.CheckerID {
+				t.Errorf("完成事件应携带结果并累加完成数量: %+v", event)
+			}
+		}
+	}
+
+	want := map[string][]EventType{
+		"adapter": {EventStarted, EventFinished},
+		"ip":      {EventFinished}, // 被跳过的检查项不会开始
+		"proxy":   {EventStarted, EventProgress, EventFinished},
+	}
+	for id, wantTypes := range want {
+		got := byChecker[id]
+		if len(got) != len(wantTypes) {
+			t.Errorf("%s 的事件为 %v，期望 %v", id, got, wantTypes)
+			continue
+		}
+		for i := range wantTypes {
+			if got[i] != wantTypes[i] {
+				t.Errorf("%s 的事件为 %v，期望 %v", id, got, wantTypes)
+				break
+			}
+		}
+	}
+}
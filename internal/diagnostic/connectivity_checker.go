@@ -28,6 +28,11 @@ func (c *ConnectivityChecker) Name() string {
 	return "电脑能否上网"
 }
 
+// DependsOn 返回依赖的检查器 ID
+func (c *ConnectivityChecker) DependsOn() []string {
+	return []string{"adapter", "dns"}
+}
+
 // Check 执行检查
 func (c *ConnectivityChecker) Check(ctx context.Context) types.DiagnosticResult {
 	result := types.NewDiagnosticResult(c.ID(), c.Name())
@@ -48,7 +53,7 @@ func (c *ConnectivityChecker) Check(ctx context.Context) types.DiagnosticResult
 	var totalLatency int64
 	var lastError string
 
-	for _, t := range targets {
+	for i, t := range targets {
 		ok, latency := t.test()
 		if ok {
 			successCount++
@@ -56,6 +61,10 @@ func (c *ConnectivityChecker) Check(ctx context.Context) types.DiagnosticResult
 		} else {
 			lastError = t.name + " 连接失败"
 		}
+
+		result.AddDetail("successCount", successCount)
+		result.Message = fmt.Sprintf("已测试 %d/%d 个目标，%d 个可达", i+1, len(targets), successCount)
+		ReportProgress(ctx, *result)
 	}
 
 	result.AddDetail("successCount", successCount)
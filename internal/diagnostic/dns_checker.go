@@ -27,6 +27,11 @@ func (c *DNSChecker) Name() string {
 	return "DNS 服务检测"
 }
 
+// DependsOn 返回依赖的检查器 ID
+func (c *DNSChecker) DependsOn() []string {
+	return []string{"ip"}
+}
+
 // Check 执行检查
 func (c *DNSChecker) Check(ctx context.Context) types.DiagnosticResult {
 	result := types.NewDiagnosticResult(c.ID(), c.Name())
@@ -36,7 +41,7 @@ func (c *DNSChecker) Check(ctx context.Context) types.DiagnosticResult {
 	var successCount int
 	var totalLatency int64
 
-	for _, domain := range testDomains {
+	for i, domain := range testDomains {
 		start := time.Now()
 		_, err := net.LookupHost(domain)
 		latency := time.Since(start).Milliseconds()
@@ -45,6 +50,9 @@ func (c *DNSChecker) Check(ctx context.Context) types.DiagnosticResult {
 			successCount++
 			totalLatency += latency
 		}
+
+		result.Message = fmt.Sprintf("已解析 %d/%d 个域名", i+1, len(testDomains))
+		ReportProgress(ctx, *result)
 	}
 
 	result.AddDetail("testedDomains", testDomains)
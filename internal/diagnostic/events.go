@@ -0,0 +1,86 @@
+package diagnostic
+
+import (
+	"context"
+	"sync"
+
+	"network-rescue-toolkit/pkg/types"
+)
+
+// EventType 诊断事件类型
+type EventType string
+
+const (
+	EventStarted  EventType = "started"  // 检查器开始运行
+	EventProgress EventType = "progress" // 检查器报告了阶段性结果
+	EventFinished EventType = "finished" // 检查器完成（含被跳过）
+)
+
+// Event 诊断事件
+type Event struct {
+	Type      EventType               `json:"type"`
+	CheckerID string                  `json:"checkerId"`
+	Name      string                  `json:"name"`
+	Result    *types.DiagnosticResult `json:"result,omitempty"` // 阶段性或最终结果
+	Completed int                     `json:"completed"`        // 已完成的检查器数量
+	Total     int                     `json:"total"`            // 本次运行的检查器总数
+}
+
+// EventHandler 诊断事件回调，引擎保证同一时间只有一个回调在执行
+type EventHandler func(Event)
+
+type progressKey struct{}
+
+// progressReporter 检查器向引擎报告阶段性结果的回调
+type progressReporter func(partial types.DiagnosticResult)
+
+// ReportProgress 检查器运行过程中报告阶段性结果（未通过引擎运行时忽略）
+func ReportProgress(ctx context.Context, partial types.DiagnosticResult) {
+	if report, ok := ctx.Value(progressKey{}).(progressReporter); ok {
+		report(partial)
+	}
+}
+
+// withProgress 在上下文中安装阶段性结果的回调
+func withProgress(ctx context.Context, report progressReporter) context.Context {
+	return context.WithValue(ctx, progressKey{}, report)
+}
+
+// eventEmitter 串行分发一次运行中的事件
+type eventEmitter struct {
+	mu        sync.Mutex
+	handler   EventHandler
+	total     int
+	completed int
+}
+
+// emit 发送事件；finished 事件会累加完成数量
+func (em *eventEmitter) emit(eventType EventType, c Checker, result *types.DiagnosticResult) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	if eventType == EventFinished {
+		em.completed++
+	}
+	if em.handler == nil {
+		return
+	}
+
+	event := Event{
+		Type:      eventType,
+		CheckerID: c.ID(),
+		Name:      c.Name(),
+		Completed: em.completed,
+		Total:     em.total,
+	}
+	if result != nil {
+		// 复制一份，避免事件接收方与检查器共享 Details
+		copied := *result
+		copied.Details = make(map[string]any, len(result.Details))
+		for k, v := range result.Details {
+			copied.Details[k] = v
+		}
+		event.Result = &copied
+	}
+	em.handler(event)
+}
@@ -0,0 +1,241 @@
+package diagnostic
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+
+	"network-rescue-toolkit/pkg/executor"
+	"network-rescue-toolkit/pkg/ipconfig"
+	"network-rescue-toolkit/pkg/netif"
+	"network-rescue-toolkit/pkg/types"
+)
+
+const (
+	// IssueGatewayOutsideSubnet 默认网关不在出口网卡的子网内，数据包无法送达网关
+	IssueGatewayOutsideSubnet = "gateway-outside-subnet"
+	// IssueCompetingRoutes 多个网卡上存在默认路由，流量可能走错出口
+	IssueCompetingRoutes = "competing-default-routes"
+)
+
+// gatewayProbe 网关检测使用的网络操作，测试时可替换
+type gatewayProbe interface {
+	// ARP 发送 ARP 请求，返回网关的 MAC 地址
+	ARP(ctx context.Context, ip string) (string, error)
+	// Ping 发送一次 ICMP 回显请求并返回往返耗时
+	Ping(ctx context.Context, ip string) (int64, error)
+	// DialTCP 建立 TCP 连接并返回耗时
+	DialTCP(ctx context.Context, addr string) (int64, error)
+}
+
+// GatewayChecker 默认路由与网关检查器
+type GatewayChecker struct {
+	executor executor.Runner
+	probe    gatewayProbe
+	pings    int
+	ports    []string
+}
+
+// NewGatewayChecker 创建默认路由与网关检查器
+func NewGatewayChecker(runner executor.Runner) *GatewayChecker {
+	return newGatewayCheckerWithProbe(runner, systemGatewayProbe{})
+}
+
+// newGatewayCheckerWithProbe 使用指定的网络操作创建默认路由与网关检查器
+func newGatewayCheckerWithProbe(runner executor.Runner, probe gatewayProbe) *GatewayChecker {
+	return &GatewayChecker{
+		executor: runner,
+		probe:    probe,
+		pings:    4,
+		ports:    []string{"80", "443", "53"}, // 路由器管理页面和 DNS 转发
+	}
+}
+
+// ID 返回检查器 ID
+func (c *GatewayChecker) ID() string {
+	return "gateway"
+}
+
+// Name 返回检查器名称
+func (c *GatewayChecker) Name() string {
+	return "路由器（默认网关）"
+}
+
+// DependsOn 返回依赖的检查器 ID
+func (c *GatewayChecker) DependsOn() []string {
+	return []string{"ip"}
+}
+
+// Check 执行检查
+func (c *GatewayChecker) Check(ctx context.Context) types.DiagnosticResult {
+	result := types.NewDiagnosticResult(c.ID(), c.Name())
+
+	routes, err := defaultRoutes(ctx, c.executor)
+	if err != nil {
+		result.SetError(err.Error(), false)
+		return *result
+	}
+	result.AddDetail("routes", routes)
+	result.AddDetail("routeCount", len(routes))
+	if len(routes) == 0 {
+		result.SetError("没有默认路由，无法访问本地网络以外的地址", true)
+		return *result
+	}
+
+	primary := routes[0]
+	result.AddDetail("gateway", primary.Gateway)
+
+	var issues, warnings []string
+	if gateways := competingGateways(routes); len(gateways) > 1 {
+		issues = append(issues, IssueCompetingRoutes)
+		warnings = append(warnings, fmt.Sprintf("%d 个网卡上存在默认路由（%s），流量可能走错出口", len(gateways), strings.Join(gateways, "、")))
+	}
+	if ipResult := c.executor.Execute(ctx, "ipconfig", "/all"); ipResult.IsSuccess() {
+		if subnet, ok := interfaceSubnet(ipconfig.ParseReport(ipResult.Stdout), primary.InterfaceAddr); ok {
+			result.AddDetail("subnet", subnet.String())
+			if gateway, err := netip.ParseAddr(primary.Gateway); err == nil && !subnet.Contains(gateway) {
+				issues = append(issues, IssueGatewayOutsideSubnet)
+				warnings = append(warnings, fmt.Sprintf("网关不在网卡子网 %s 内", subnet))
+			}
+		}
+	}
+	result.AddDetail("issues", issues)
+
+	result.Message = "正在测试网关 " + primary.Gateway
+	ReportProgress(ctx, *result)
+
+	reach := c.probeGateway(ctx, primary.Gateway)
+	result.AddDetail("arp", reach.mac)
+	result.AddDetail("pingSent", c.pings)
+	result.AddDetail("pingReceived", reach.received)
+	result.AddDetail("lossPercent", reach.lossPercent(c.pings))
+	result.AddDetail("tcpPort", reach.tcpPort)
+	result.AddDetail("avgLatencyMs", reach.latency)
+
+	if !reach.reachable() {
+		result.SetError(fmt.Sprintf("默认网关 %s 无响应，路由器或本地链路故障", primary.Gateway), true)
+		return *result
+	}
+
+	if reach.received > 0 {
+		if loss := reach.lossPercent(c.pings); loss > 0 {
+			warnings = append(warnings, fmt.Sprintf("到网关丢包 %d%%", loss))
+		}
+	}
+	if reach.latency >= 100 {
+		warnings = append(warnings, fmt.Sprintf("到网关延迟 %dms 偏高", reach.latency))
+	}
+	if len(warnings) > 0 {
+		result.SetWarning(fmt.Sprintf("默认网关 %s 可达，但%s", primary.Gateway, strings.Join(warnings, "；")), false)
+		return *result
+	}
+
+	result.SetOK(fmt.Sprintf("默认网关 %s 可达，延迟 %dms", primary.Gateway, reach.latency))
+	return *result
+}
+
+// defaultRoutes 读取路由表中的 IPv4 默认路由，按有效跃点数从小到大排列
+func defaultRoutes(ctx context.Context, runner executor.Runner) ([]netif.Route, error) {
+	cmdResult := runner.Execute(ctx, "route", "print", "-4", "0.0.0.0")
+	if !cmdResult.IsSuccess() {
+		return nil, fmt.Errorf("无法读取路由表: %s", cmdResult.Stderr)
+	}
+	return netif.ParseRoutePrint(cmdResult.Stdout), nil
+}
+
+// gatewayReach 网关可达性测试结果
+type gatewayReach struct {
+	mac      string // ARP 解析到的 MAC 地址
+	received int    // 收到的 ICMP 回显应答数
+	tcpPort  string // 能建立连接的 TCP 端口
+	latency  int64  // 平均延迟（优先使用 ICMP）
+}
+
+// reachable 任一方式得到响应即认为网关可达（部分路由器不响应 ping）
+func (r gatewayReach) reachable() bool {
+	return r.mac != "" || r.received > 0 || r.tcpPort != ""
+}
+
+// lossPercent 计算 ICMP 丢包率
+func (r gatewayReach) lossPercent(sent int) int {
+	if sent == 0 {
+		return 0
+	}
+	return (sent - r.received) * 100 / sent
+}
+
+// probeGateway 依次通过 ARP、ICMP 和 TCP 测试网关
+func (c *GatewayChecker) probeGateway(ctx context.Context, gateway string) gatewayReach {
+	var reach gatewayReach
+	if mac, err := c.probe.ARP(ctx, gateway); err == nil {
+		reach.mac = mac
+	}
+
+	var total int64
+	for i := 0; i < c.pings; i++ {
+		if latency, err := c.probe.Ping(ctx, gateway); err == nil {
+			reach.received++
+			total += latency
+		}
+	}
+	if reach.received > 0 {
+		reach.latency = total / int64(reach.received)
+		return reach
+	}
+
+	// 网关不响应 ping 时用 TCP 连接测量延迟
+	for _, port := range c.ports {
+		if latency, err := c.probe.DialTCP(ctx, net.JoinHostPort(gateway, port)); err == nil {
+			reach.tcpPort = port
+			reach.latency = latency
+			break
+		}
+	}
+	return reach
+}
+
+// competingGateways 返回不同出口网卡上的默认网关（每个出口取跃点数最小的一条）
+func competingGateways(routes []netif.Route) []string {
+	seen := make(map[string]bool)
+	var gateways []string
+	for _, route := range routes {
+		if seen[route.InterfaceAddr] {
+			continue
+		}
+		seen[route.InterfaceAddr] = true
+		gateways = append(gateways, fmt.Sprintf("%s 跃点数 %d", route.Gateway, route.Metric))
+	}
+	return gateways
+}
+
+// interfaceSubnet 找到 IPv4 地址所在网卡的子网
+func interfaceSubnet(report ipconfig.Report, addr string) (netip.Prefix, bool) {
+	for _, adapter := range report.Adapters {
+		for _, ipv4 := range adapter.IPv4 {
+			if ipv4.Address != addr || ipv4.PrefixLength == 0 {
+				continue
+			}
+			parsed, err := netip.ParseAddr(addr)
+			if err != nil {
+				return netip.Prefix{}, false
+			}
+			return netip.PrefixFrom(parsed, ipv4.PrefixLength).Masked(), true
+		}
+	}
+	return netip.Prefix{}, false
+}
+
+// DialTCP 建立 TCP 连接并返回耗时
+func (systemGatewayProbe) DialTCP(ctx context.Context, addr string) (int64, error) {
+	dialer := net.Dialer{Timeout: time.Second}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp4", addr)
+	if err != nil {
+		return 0, err
+	}
+	conn.Close()
+	return time.Since(start).Milliseconds(), nil
+}
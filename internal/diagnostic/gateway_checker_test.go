@@ -0,0 +1,140 @@
+package diagnostic
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"network-rescue-toolkit/pkg/executor"
+	"network-rescue-toolkit/pkg/types"
+)
+
+// sampleRoutePrint 中文系统的 route print -4 0.0.0.0 输出样本
+const sampleRoutePrint = `
+IPv4 路由表
+===========================================================================
+活动路由:
+网络目标        网络掩码          网关       接口   跃点数
+          0.0.0.0          0.0.0.0      192.168.1.1     192.168.1.20     25
+===========================================================================
+永久路由:
+  无
+`
+
+// fakeGatewayProbe 按预设结果响应的网关检测
+type fakeGatewayProbe struct {
+	mac   string
+	pings []int64 // 负数表示超时
+	tcp   map[string]int64
+	calls int
+}
+
+func (p *fakeGatewayProbe) ARP(ctx context.Context, ip string) (string, error) {
+	if p.mac == "" {
+		return "", errors.New("无应答")
+	}
+	return p.mac, nil
+}
+
+func (p *fakeGatewayProbe) Ping(ctx context.Context, ip string) (int64, error) {
+	latency := int64(-1)
+	if p.calls < len(p.pings) {
+		latency = p.pings[p.calls]
+	}
+	p.calls++
+	if latency < 0 {
+		return 0, errors.New("请求超时")
+	}
+	return latency, nil
+}
+
+func (p *fakeGatewayProbe) DialTCP(ctx context.Context, addr string) (int64, error) {
+	if latency, ok := p.tcp[addr]; ok {
+		return latency, nil
+	}
+	return 0, errors.New("连接被拒绝")
+}
+
+func TestGatewayChecker(t *testing.T) {
+	tests := []struct {
+		name    string
+		routes  string
+		probe   *fakeGatewayProbe
+		status  types.DiagnosticStatus
+		message string
+	}{
+		{
+			name:    "网关正常",
+			routes:  sampleRoutePrint,
+			probe:   &fakeGatewayProbe{mac: "aa:bb:cc:dd:ee:ff", pings: []int64{2, 3, 2, 1}},
+			status:  types.StatusOK,
+			message: "默认网关 192.168.1.1 可达，延迟 2ms",
+		},
+		{
+			name:    "网关不响应 ping 但 TCP 可连接",
+			routes:  sampleRoutePrint,
+			probe:   &fakeGatewayProbe{tcp: map[string]int64{"192.168.1.1:443": 4}},
+			status:  types.StatusOK,
+			message: "默认网关 192.168.1.1 可达，延迟 4ms",
+		},
+		{
+			name:    "丢包",
+			routes:  sampleRoutePrint,
+			probe:   &fakeGatewayProbe{mac: "aa:bb:cc:dd:ee:ff", pings: []int64{2, -1, 4, -1}},
+			status:  types.StatusWarning,
+			message: "默认网关 192.168.1.1 可达，但到网关丢包 50%",
+		},
+		{
+			name:    "网关无响应",
+			routes:  sampleRoutePrint,
+			probe:   &fakeGatewayProbe{},
+			status:  types.StatusError,
+			message: "默认网关 192.168.1.1 无响应，路由器或本地链路故障",
+		},
+		{
+			name:    "没有默认路由",
+			routes:  "IPv4 路由表\n活动路由:\n无\n",
+			probe:   &fakeGatewayProbe{},
+			status:  types.StatusError,
+			message: "没有默认路由，无法访问本地网络以外的地址",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := executor.NewFakeRunner().
+				OnOutput("route print -4 0.0.0.0", tt.routes).
+				OnOutput("ipconfig /all", sampleIPConfig)
+
+			result := newGatewayCheckerWithProbe(fake, tt.probe).Check(context.Background())
+			if result.Status != tt.status || result.Message != tt.message {
+				t.Errorf("结果为 %s %q，期望 %s %q", result.Status, result.Message, tt.status, tt.message)
+			}
+		})
+	}
+}
+
+func TestGatewayCheckerFlagsRouteProblems(t *testing.T) {
+	routes := `
+活动路由:
+网络目标        网络掩码          网关       接口   跃点数
+          0.0.0.0          0.0.0.0      192.168.2.1     192.168.1.20     25
+          0.0.0.0          0.0.0.0      10.0.0.1        10.0.0.8         25
+`
+	fake := executor.NewFakeRunner().
+		OnOutput("route print -4 0.0.0.0", routes).
+		OnOutput("ipconfig /all", sampleIPConfig)
+	probe := &fakeGatewayProbe{mac: "aa:bb:cc:dd:ee:ff", pings: []int64{1, 1, 1, 1}}
+
+	result := newGatewayCheckerWithProbe(fake, probe).Check(context.Background())
+	if result.Status != types.StatusWarning {
+		t.Fatalf("多条默认路由且网关不在子网内时应报告警告: %+v", result)
+	}
+	issues := result.Details["issues"].([]string)
+	if len(issues) != 2 || issues[0] != IssueCompetingRoutes || issues[1] != IssueGatewayOutsideSubnet {
+		t.Errorf("应同时标记两个问题: %v", issues)
+	}
+	if result.Details["subnet"] != "192.168.1.0/24" {
+		t.Errorf("出口网卡子网不符: %v", result.Details["subnet"])
+	}
+}
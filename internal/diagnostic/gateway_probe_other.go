@@ -0,0 +1,37 @@
+//go:build !windows
+
+package diagnostic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// errICMPUnsupported 当前系统不支持 ICMP 检测
+var errICMPUnsupported = errors.New("当前系统不支持 ICMP 检测")
+
+// systemGatewayProbe 读取系统 ARP 缓存的网关检测实现（不支持 ICMP）
+type systemGatewayProbe struct{}
+
+// ARP 从 /proc/net/arp 查找网关的 MAC 地址
+func (systemGatewayProbe) ARP(ctx context.Context, ip string) (string, error) {
+	data, err := os.ReadFile("/proc/net/arp")
+	if err != nil {
+		return "", fmt.Errorf("读取 ARP 缓存失败: %w", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 4 && fields[0] == ip && fields[3] != "00:00:00:00:00:00" {
+			return fields[3], nil
+		}
+	}
+	return "", fmt.Errorf("ARP 缓存中没有 %s", ip)
+}
+
+// Ping 当前系统不支持
+func (systemGatewayProbe) Ping(ctx context.Context, ip string) (int64, error) {
+	return 0, errICMPUnsupported
+}
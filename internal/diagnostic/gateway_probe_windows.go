@@ -0,0 +1,80 @@
+//go:build windows
+
+package diagnostic
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	iphlpapi            = windows.NewLazySystemDLL("iphlpapi.dll")
+	procSendARP         = iphlpapi.NewProc("SendARP")
+	procIcmpCreateFile  = iphlpapi.NewProc("IcmpCreateFile")
+	procIcmpSendEcho    = iphlpapi.NewProc("IcmpSendEcho")
+	procIcmpCloseHandle = iphlpapi.NewProc("IcmpCloseHandle")
+)
+
+// pingTimeoutMs 单次 ICMP 回显请求的超时时间
+const pingTimeoutMs = 1000
+
+// systemGatewayProbe 使用 iphlpapi 的网关检测实现
+type systemGatewayProbe struct{}
+
+// ARP 通过 SendARP 解析网关的 MAC 地址
+func (systemGatewayProbe) ARP(ctx context.Context, ip string) (string, error) {
+	dest, err := ipv4Addr(ip)
+	if err != nil {
+		return "", err
+	}
+	var mac [8]byte
+	size := uint32(len(mac))
+	ret, _, _ := procSendARP.Call(uintptr(dest), 0, uintptr(unsafe.Pointer(&mac[0])), uintptr(unsafe.Pointer(&size)))
+	if ret != 0 {
+		return "", fmt.Errorf("ARP 请求失败: %w", windows.Errno(ret))
+	}
+	return net.HardwareAddr(mac[:size]).String(), nil
+}
+
+// Ping 通过 IcmpSendEcho 发送一次 ICMP 回显请求
+func (systemGatewayProbe) Ping(ctx context.Context, ip string) (int64, error) {
+	dest, err := ipv4Addr(ip)
+	if err != nil {
+		return 0, err
+	}
+	handle, _, callErr := procIcmpCreateFile.Call()
+	if windows.Handle(handle) == windows.InvalidHandle {
+		return 0, fmt.Errorf("创建 ICMP 句柄失败: %w", callErr)
+	}
+	defer procIcmpCloseHandle.Call(handle)
+
+	payload := []byte("network-rescue-toolkit")
+	// 应答缓冲区：ICMP_ECHO_REPLY 结构 + 回显数据 + ICMP 错误信息
+	reply := make([]byte, 64+len(payload)+8)
+	n, _, callErr := procIcmpSendEcho.Call(handle, uintptr(dest),
+		uintptr(unsafe.Pointer(&payload[0])), uintptr(len(payload)), 0,
+		uintptr(unsafe.Pointer(&reply[0])), uintptr(len(reply)), pingTimeoutMs)
+	if n == 0 {
+		return 0, fmt.Errorf("ICMP 请求无应答: %w", callErr)
+	}
+
+	// ICMP_ECHO_REPLY: Address(4) Status(4) RoundTripTime(4) ...
+	if status := binary.LittleEndian.Uint32(reply[4:8]); status != 0 {
+		return 0, fmt.Errorf("ICMP 请求失败，状态码 %d", status)
+	}
+	return int64(binary.LittleEndian.Uint32(reply[8:12])), nil
+}
+
+// ipv4Addr 转换为 iphlpapi 使用的 IPAddr（网络字节序）
+func ipv4Addr(ip string) (uint32, error) {
+	parsed := net.ParseIP(ip).To4()
+	if parsed == nil {
+		return 0, fmt.Errorf("无效的 IPv4 地址: %s", ip)
+	}
+	return binary.LittleEndian.Uint32(parsed), nil
+}
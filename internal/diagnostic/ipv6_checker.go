@@ -0,0 +1,287 @@
+package diagnostic
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"network-rescue-toolkit/pkg/executor"
+	"network-rescue-toolkit/pkg/ipconfig"
+	"network-rescue-toolkit/pkg/netif"
+	"network-rescue-toolkit/pkg/types"
+)
+
+// IssueIPv6SlowFallback IPv6 看似可用但实际不通，程序需等待 IPv6 超时后才回退到 IPv4
+const IssueIPv6SlowFallback = "ipv6-slow-fallback"
+
+// ipv6Probe IPv6 检测使用的网络操作，测试时可替换
+type ipv6Probe interface {
+	// LookupAAAA 解析域名的 AAAA 记录
+	LookupAAAA(ctx context.Context, host string) ([]string, error)
+	// DialTCP 建立 TCP 连接并返回耗时，network 为 tcp4 或 tcp6
+	DialTCP(ctx context.Context, network, addr string) (int64, error)
+	// HTTPHead 通过指定协议族发送 HEAD 请求并返回耗时
+	HTTPHead(ctx context.Context, network, url string) (int64, error)
+}
+
+// ipv6Target IPv6 连通性测试目标
+type ipv6Target struct {
+	name    string
+	network string
+	addr    string // TCP 为 host:port，HTTP 为 URL
+	http    bool
+}
+
+// IPv6Checker IPv6 检查器
+type IPv6Checker struct {
+	executor executor.Runner
+	probe    ipv6Probe
+	domains  []string
+	targets  []ipv6Target
+	ipv4Addr string
+}
+
+// NewIPv6Checker 创建 IPv6 检查器
+func NewIPv6Checker(runner executor.Runner) *IPv6Checker {
+	return newIPv6CheckerWithProbe(runner, systemIPv6Probe{})
+}
+
+// newIPv6CheckerWithProbe 使用指定的网络操作创建 IPv6 检查器
+func newIPv6CheckerWithProbe(runner executor.Runner, probe ipv6Probe) *IPv6Checker {
+	return &IPv6Checker{
+		executor: runner,
+		probe:    probe,
+		domains:  []string{"www.qq.com", "www.taobao.com"},
+		targets: []ipv6Target{
+			{name: "HTTP-腾讯", network: "tcp6", addr: "https://www.qq.com", http: true},
+			{name: "TCP-阿里DNS", network: "tcp6", addr: "[2400:3200::1]:53"},
+			{name: "TCP-DNSPod", network: "tcp6", addr: "[2402:4e00::]:53"},
+		},
+		ipv4Addr: "223.5.5.5:53",
+	}
+}
+
+// ID 返回检查器 ID
+func (c *IPv6Checker) ID() string {
+	return "ipv6"
+}
+
+// Name 返回检查器名称
+func (c *IPv6Checker) Name() string {
+	return "IPv6 检测"
+}
+
+// DependsOn 返回依赖的检查器 ID
+func (c *IPv6Checker) DependsOn() []string {
+	return []string{"adapter"}
+}
+
+// Check 执行检查
+func (c *IPv6Checker) Check(ctx context.Context) types.DiagnosticResult {
+	result := types.NewDiagnosticResult(c.ID(), c.Name())
+
+	// 地址：区分本地链接地址与全局地址
+	cmdResult := c.executor.Execute(ctx, "ipconfig", "/all")
+	if !cmdResult.IsSuccess() {
+		result.SetError("无法获取 IP 配置信息: "+cmdResult.Stderr, false)
+		return *result
+	}
+	global, linkLocal := classifyIPv6(ipconfig.ParseReport(cmdResult.Stdout))
+	result.AddDetail("globalAddresses", global)
+	result.AddDetail("linkLocalAddresses", linkLocal)
+
+	if len(global) == 0 {
+		result.SetOK("未分配 IPv6 公网地址，使用 IPv4 上网")
+		return *result
+	}
+
+	// 默认路由 ::/0
+	hasRoute := c.hasDefaultRoute(ctx)
+	result.AddDetail("defaultRoute", hasRoute)
+	if !hasRoute {
+		// 没有默认路由时系统不会尝试 IPv6 连接，不会拖慢访问
+		result.SetWarning("已分配 IPv6 地址但没有 IPv6 默认路由", false)
+		return *result
+	}
+
+	// AAAA 解析
+	aaaaCount := 0
+	for _, domain := range c.domains {
+		if addrs, err := c.probe.LookupAAAA(ctx, domain); err == nil {
+			aaaaCount += len(addrs)
+		}
+	}
+	result.AddDetail("aaaaRecords", aaaaCount)
+
+	// IPv6 连通性
+	successCount, avgLatency, lastError := c.testTargets(ctx)
+	result.AddDetail("successCount", successCount)
+	result.AddDetail("totalTargets", len(c.targets))
+
+	if successCount > 0 {
+		result.AddDetail("avgLatencyMs", avgLatency)
+		if aaaaCount == 0 {
+			result.SetWarning("IPv6 连通正常，但 DNS 未返回 AAAA 记录", false)
+		} else {
+			result.SetOK(fmt.Sprintf("IPv6 连通正常，平均延迟 %dms", avgLatency))
+		}
+		return *result
+	}
+
+	// IPv6 全部失败：IPv4 正常时即为 "IPv6 故障导致回退缓慢"
+	if _, err := c.probe.DialTCP(ctx, "tcp4", c.ipv4Addr); err != nil {
+		result.SetWarning("IPv6 与 IPv4 均无法连通: "+lastError, false)
+		return *result
+	}
+
+	// 已调整前缀策略优先使用 IPv4 时，IPv6 不通也不会拖慢访问
+	if c.prefersIPv4(ctx) {
+		result.AddDetail("ipv4Preferred", true)
+		result.SetWarning("IPv6 无法连通，但已设置为优先使用 IPv4，访问网站不会等待 IPv6 超时", false)
+		return *result
+	}
+
+	result.AddDetail("issue", IssueIPv6SlowFallback)
+	result.SetWarning("IPv6 已启用但无法连通，访问网站需等待 IPv6 超时后才回退到 IPv4，可能导致打开缓慢", true)
+	return *result
+}
+
+// hasDefaultRoute 检查是否存在 IPv6 默认路由
+func (c *IPv6Checker) hasDefaultRoute(ctx context.Context) bool {
+	cmdResult := c.executor.Execute(ctx, "netsh", "interface", "ipv6", "show", "route")
+	if !cmdResult.IsSuccess() {
+		return false
+	}
+	for _, field := range strings.Fields(cmdResult.Stdout) {
+		if field == "::/0" {
+			return true
+		}
+	}
+	return false
+}
+
+// prefersIPv4 检查前缀策略是否已让 IPv4 映射地址优先于 IPv6
+func (c *IPv6Checker) prefersIPv4(ctx context.Context) bool {
+	cmdResult := c.executor.Execute(ctx, "netsh", "interface", "ipv6", "show", "prefixpolicies")
+	if !cmdResult.IsSuccess() {
+		return false
+	}
+	return netif.PrefersIPv4(netif.ParsePrefixPolicies(cmdResult.Stdout))
+}
+
+// testTargets 测试 IPv6 目标，返回成功数、平均延迟和最后一个错误
+func (c *IPv6Checker) testTargets(ctx context.Context) (int, int64, string) {
+	var successCount int
+	var totalLatency int64
+	var lastError string
+
+	for _, t := range c.targets {
+		var latency int64
+		var err error
+		if t.http {
+			latency, err = c.probe.HTTPHead(ctx, t.network, t.addr)
+		} else {
+			latency, err = c.probe.DialTCP(ctx, t.network, t.addr)
+		}
+		if err != nil {
+			lastError = t.name + " 连接失败"
+			continue
+		}
+		successCount++
+		totalLatency += latency
+	}
+
+	if successCount == 0 {
+		return 0, 0, lastError
+	}
+	return successCount, totalLatency / int64(successCount), lastError
+}
+
+// classifyIPv6 汇总所有适配器的 IPv6 地址
+// Teredo (2001::/32) 与 6to4 (2002::/16) 隧道地址不算作原生全局地址
+func classifyIPv6(report ipconfig.Report) (global, linkLocal []string) {
+	teredo := netip.MustParsePrefix("2001::/32")
+	sixToFour := netip.MustParsePrefix("2002::/16")
+
+	global = make([]string, 0)
+	linkLocal = make([]string, 0)
+	for _, adapter := range report.Adapters {
+		for _, addr := range adapter.IPv6 {
+			switch addr.Scope {
+			case ipconfig.ScopeLinkLocal:
+				linkLocal = append(linkLocal, addr.Address)
+			case ipconfig.ScopeGlobal, ipconfig.ScopeTemporary:
+				parsed, err := netip.ParseAddr(addr.Address)
+				if err != nil || teredo.Contains(parsed) || sixToFour.Contains(parsed) {
+					continue
+				}
+				global = append(global, addr.Address)
+			}
+		}
+	}
+	return global, linkLocal
+}
+
+// systemIPv6Probe 使用系统网络栈的 IPv6 检测实现
+type systemIPv6Probe struct{}
+
+// LookupAAAA 解析域名的 AAAA 记录
+func (systemIPv6Probe) LookupAAAA(ctx context.Context, host string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip6", host)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, ip.String())
+	}
+	return addrs, nil
+}
+
+// DialTCP 建立 TCP 连接并返回耗时
+func (systemIPv6Probe) DialTCP(ctx context.Context, network, addr string) (int64, error) {
+	dialer := net.Dialer{Timeout: 5 * time.Second}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, network, addr)
+	if err != nil {
+		return 0, err
+	}
+	conn.Close()
+	return time.Since(start).Milliseconds(), nil
+}
+
+// HTTPHead 通过指定协议族发送 HEAD 请求并返回耗时
+func (systemIPv6Probe) HTTPHead(ctx context.Context, network, url string) (int64, error) {
+	dialer := net.Dialer{Timeout: 5 * time.Second}
+	client := &http.Client{
+		Timeout: 8 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, addr string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, addr)
+			},
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse // 不跟随重定向
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return time.Since(start).Milliseconds(), nil
+}
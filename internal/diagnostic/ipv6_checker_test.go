@@ -0,0 +1,105 @@
+package diagnostic
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"network-rescue-toolkit/pkg/executor"
+	"network-rescue-toolkit/pkg/types"
+)
+
+// sampleIPv6Config 带全局 IPv6 地址的 ipconfig /all 输出
+const sampleIPv6Config = `
+Windows IP 配置
+
+无线局域网适配器 WLAN:
+
+   连接特定的 DNS 后缀 . . . . . . . : lan
+   DHCP 已启用 . . . . . . . . . . . : 是
+   IPv6 地址 . . . . . . . . . . . . : 240e:3b4:38e1:1e90::1005(首选)
+   本地链接 IPv6 地址. . . . . . . . : fe80::8d41:2b7c:6e95:1a3f%18(首选)
+   IPv4 地址 . . . . . . . . . . . . : 192.168.1.105(首选)
+   子网掩码  . . . . . . . . . . . . : 255.255.255.0
+   默认网关. . . . . . . . . . . . . : fe80::1%18
+                                       192.168.1.1
+`
+
+const sampleIPv6Routes = `
+发布    类型      跃点数  前缀                    索引  网关/接口名
+-------  --------  ---  ------------------------  ---  ------------------------
+否       手动    256  ::/0                       18  fe80::1
+否       系统    256  ::1/128                     1  Loopback Pseudo-Interface 1
+`
+
+// fakeIPv6Probe 按协议族返回预设结果的网络操作
+type fakeIPv6Probe struct {
+	aaaa   []string
+	ipv6OK bool
+	ipv4OK bool
+}
+
+func (p fakeIPv6Probe) LookupAAAA(ctx context.Context, host string) ([]string, error) {
+	return p.aaaa, nil
+}
+
+func (p fakeIPv6Probe) DialTCP(ctx context.Context, network, addr string) (int64, error) {
+	if (network == "tcp6" && p.ipv6OK) || (network == "tcp4" && p.ipv4OK) {
+		return 20, nil
+	}
+	return 0, errors.New("i/o timeout")
+}
+
+func (p fakeIPv6Probe) HTTPHead(ctx context.Context, network, url string) (int64, error) {
+	return p.DialTCP(ctx, network, url)
+}
+
+func TestIPv6CheckerResults(t *testing.T) {
+	ipv4Only := `
+以太网适配器 以太网:
+
+   本地链接 IPv6 地址. . . . . . . . : fe80::1c2d:3e4f:5a6b:7c8d%12(首选)
+   IPv4 地址 . . . . . . . . . . . . : 192.168.10.20(首选)
+`
+	preferIPv4 := `
+优先顺序  标签  前缀
+----------  -----  --------------------------------
+        50      0  ::1/128
+       100      4  ::ffff:0:0/96
+        40      1  ::/0
+`
+	cases := []struct {
+		name       string
+		ipconfig   string
+		routes     string
+		policies   string
+		probe      fakeIPv6Probe
+		status     types.DiagnosticStatus
+		repairable bool
+		issue      bool
+	}{
+		{"仅本地链接地址", ipv4Only, sampleIPv6Routes, "", fakeIPv6Probe{}, types.StatusOK, false, false},
+		{"没有默认路由", sampleIPv6Config, "", "", fakeIPv6Probe{}, types.StatusWarning, false, false},
+		{"IPv6 正常", sampleIPv6Config, sampleIPv6Routes, "", fakeIPv6Probe{aaaa: []string{"240e::1"}, ipv6OK: true, ipv4OK: true}, types.StatusOK, false, false},
+		{"IPv6 故障回退缓慢", sampleIPv6Config, sampleIPv6Routes, "", fakeIPv6Probe{aaaa: []string{"240e::1"}, ipv4OK: true}, types.StatusWarning, true, true},
+		{"IPv6 故障但已优先使用 IPv4", sampleIPv6Config, sampleIPv6Routes, preferIPv4, fakeIPv6Probe{aaaa: []string{"240e::1"}, ipv4OK: true}, types.StatusWarning, false, false},
+		{"完全断网", sampleIPv6Config, sampleIPv6Routes, "", fakeIPv6Probe{}, types.StatusWarning, false, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fake := executor.NewFakeRunner().
+				OnOutput("ipconfig /all", c.ipconfig).
+				OnOutput("netsh interface ipv6 show route", c.routes).
+				OnOutput("netsh interface ipv6 show prefixpolicies", c.policies)
+
+			result := newIPv6CheckerWithProbe(fake, c.probe).Check(context.Background())
+			if result.Status != c.status || result.Repairable != c.repairable {
+				t.Errorf("结果不符: %+v", result)
+			}
+			if issue := result.Details["issue"] == IssueIPv6SlowFallback; issue != c.issue {
+				t.Errorf("回退缓慢标记不符: %+v", result.Details)
+			}
+		})
+	}
+}
@@ -3,16 +3,20 @@ package diagnostic
 import (
 	"context"
 
-	"golang.org/x/sys/windows/registry"
+	"network-rescue-toolkit/pkg/registry"
 	"network-rescue-toolkit/pkg/types"
 )
 
 // ProxyChecker 代理设置检查器
-type ProxyChecker struct{}
+type ProxyChecker struct {
+	store registry.Store
+}
 
 // NewProxyChecker 创建代理设置检查器
-func NewProxyChecker() *ProxyChecker {
-	return &ProxyChecker{}
+func NewProxyChecker(store registry.Store) *ProxyChecker {
+	return &ProxyChecker{
+		store: store,
+	}
 }
 
 // ID 返回检查器 ID
@@ -45,34 +49,26 @@ func (c *ProxyChecker) Check(ctx context.Context) types.DiagnosticResult {
 func (c *ProxyChecker) readProxySettings() types.ProxyConfig {
 	config := types.ProxyConfig{}
 
-	key, err := registry.OpenKey(registry.CURRENT_USER,
-		`Software\Microsoft\Windows\CurrentVersion\Internet Settings`,
-		registry.QUERY_VALUE)
-	if err != nil {
-		return config
-	}
-	defer key.Close()
-
 	// 读取代理启用状态
-	proxyEnable, _, err := key.GetIntegerValue("ProxyEnable")
+	proxyEnable, err := c.store.ReadDWORD(registry.ProxySettingsPath, "ProxyEnable")
 	if err == nil {
 		config.Enabled = proxyEnable == 1
 	}
 
 	// 读取代理服务器地址
-	proxyServer, _, err := key.GetStringValue("ProxyServer")
+	proxyServer, err := c.store.ReadString(registry.ProxySettingsPath, "ProxyServer")
 	if err == nil {
 		config.Server = proxyServer
 	}
 
 	// 读取代理绕过列表
-	proxyOverride, _, err := key.GetStringValue("ProxyOverride")
+	proxyOverride, err := c.store.ReadString(registry.ProxySettingsPath, "ProxyOverride")
 	if err == nil {
 		config.BypassList = proxyOverride
 	}
 
 	// 读取自动配置 URL
-	autoConfigURL, _, err := key.GetStringValue("AutoConfigURL")
+	autoConfigURL, err := c.store.ReadString(registry.ProxySettingsPath, "AutoConfigURL")
 	if err == nil {
 		config.AutoConfigURL = autoConfigURL
 	}
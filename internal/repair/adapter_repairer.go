@@ -2,24 +2,71 @@ package repair
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"network-rescue-toolkit/internal/diagnostic"
 	"network-rescue-toolkit/pkg/executor"
 	"network-rescue-toolkit/pkg/types"
 )
 
+// AdapterSelection 选择要重置的网卡
+// 未指定名称和索引时只重置没拿到地址的物理网卡（承载默认路由的排在最前），都正常时重置承载默认路由的网卡
+// 虚拟网卡（Hyper-V、VPN、VirtualBox 等）默认跳过；未连接或已禁用的网卡只在按名称或索引指定时重置
+type AdapterSelection struct {
+	Name           string `json:"name"`           // 指定网卡名称
+	Index          int    `json:"index"`          // 指定网卡索引（大于 0 时生效）
+	All            bool   `json:"all"`            // 重置所有已连接的物理网卡，而不只是异常的网卡
+	IncludeVirtual bool   `json:"includeVirtual"` // 同时重置虚拟网卡
+}
+
+// AdapterLister 列出本机网卡
+type AdapterLister func(ctx context.Context) ([]types.AdapterInfo, error)
+
 // AdapterRepairer 网络适配器修复器
 type AdapterRepairer struct {
-	executor *executor.CommandExecutor
+	executor  executor.Runner
+	list      AdapterLister
+	selection AdapterSelection
+	pause     time.Duration
+}
+
+// NewAdapterRepairer 创建网络适配器修复器，使用网络适配器检测的结果选择网卡
+func NewAdapterRepairer(runner executor.Runner) *AdapterRepairer {
+	return newAdapterRepairerWithLister(runner, checkerAdapters(runner))
 }
 
-// NewAdapterRepairer 创建网络适配器修复器
-func NewAdapterRepairer() *AdapterRepairer {
+// newAdapterRepairerWithLister 使用指定的网卡来源创建网络适配器修复器
+func newAdapterRepairerWithLister(runner executor.Runner, list AdapterLister) *AdapterRepairer {
 	return &AdapterRepairer{
-		executor: executor.NewCommandExecutor(),
+		executor: runner,
+		list:     list,
+		pause:    2 * time.Second,
 	}
 }
 
+// checkerAdapters 返回运行网络适配器检测获取网卡的 AdapterLister
+func checkerAdapters(runner executor.Runner) AdapterLister {
+	return func(ctx context.Context) ([]types.AdapterInfo, error) {
+		result := diagnostic.NewAdapterChecker(runner).Check(ctx)
+		adapters, ok := result.Details["adapters"].([]types.AdapterInfo)
+		if !ok {
+			return nil, fmt.Errorf("获取网卡列表失败: %s", result.Message)
+		}
+		return adapters, nil
+	}
+}
+
+// WithSelection 返回使用指定网卡选择的修复器副本
+func (r *AdapterRepairer) WithSelection(selection AdapterSelection) *AdapterRepairer {
+	copied := *r
+	copied.selection = selection
+	return &copied
+}
+
 // ID 返回修复器 ID
 func (r *AdapterRepairer) ID() string {
 	return "adapter"
@@ -35,33 +82,157 @@ func (r *AdapterRepairer) RequiresAdmin() bool {
 	return true
 }
 
-// Repair 执行修复
+// ModifiesConfig 是否会修改系统网络配置
+func (r *AdapterRepairer) ModifiesConfig() bool {
+	return true
+}
+
+// Targets 返回该修复器能解决的诊断项
+func (r *AdapterRepairer) Targets() []string {
+	return []string{"adapter", "ip", "gateway", "connectivity"}
+}
+
+// Invasiveness 影响程度（重启网卡会短暂断网）
+func (r *AdapterRepairer) Invasiveness() int {
+	return 60
+}
+
+// VerifiedBy 返回修复后用于验证的诊断项
+func (r *AdapterRepairer) VerifiedBy() []string {
+	return []string{"adapter"}
+}
+
+// Repair 执行修复：依次禁用再启用选中的网卡
 func (r *AdapterRepairer) Repair(ctx context.Context) types.RepairResult {
 	result := types.NewRepairResult(r.ID(), r.Name())
 	result.Timestamp = time.Now()
 
-	// 获取所有网络适配器并重置
-	// 使用 netsh 禁用再启用
-	disableResult := r.executor.ExecuteNetsh(ctx, "interface", "set", "interface", "以太网", "disable")
-	if !disableResult.IsSuccess() {
-		// 尝试英文名称
-		disableResult = r.executor.ExecuteNetsh(ctx, "interface", "set", "interface", "Ethernet", "disable")
+	adapters, note, err := r.selectAdapters(ctx)
+	if err != nil {
+		result.SetFailure(err.Error())
+		return *result
 	}
 
-	// 等待 2 秒
-	time.Sleep(2 * time.Second)
+	var reset, failed []string
+	for _, adapter := range adapters {
+		if ctx.Err() != nil {
+			break
+		}
+		// 禁用失败的网卡（例如已被禁用）仍尝试启用
+		r.executor.Execute(ctx, "netsh", "interface", "set", "interface", adapter.Name, "disable")
+		cancelled := wait(ctx, r.pause)
+		// 取消时也必须重新启用已禁用的网卡，否则会让电脑断网
+		enableResult := r.executor.Execute(context.WithoutCancel(ctx), "netsh", "interface", "set", "interface", adapter.Name, "enable")
+		if enableResult.IsSuccess() {
+			reset = append(reset, adapter.Name)
+		} else {
+			failed = append(failed, adapter.Name)
+		}
+		if cancelled {
+			break
+		}
+	}
 
-	// 重新启用
-	enableResult := r.executor.ExecuteNetsh(ctx, "interface", "set", "interface", "以太网", "enable")
-	if !enableResult.IsSuccess() {
-		enableResult = r.executor.ExecuteNetsh(ctx, "interface", "set", "interface", "Ethernet", "enable")
+	switch {
+	case len(failed) > 0:
+		result.SetFailure("以下网卡重新启用失败，请手动在设备管理器中操作: " + strings.Join(failed, "、"))
+	case ctx.Err() != nil && len(reset) < len(adapters):
+		result.SetFailure("操作已取消，已重置网卡: " + strings.Join(reset, "、"))
+	default:
+		result.SetSuccess(note + "已重置网卡: " + strings.Join(reset, "、"))
 	}
+	return *result
+}
 
-	if enableResult.IsSuccess() {
-		result.SetSuccess("网络适配器已重置")
-	} else {
-		result.SetFailure("网络适配器重置失败，请手动在设备管理器中操作")
+// wait 等待指定时间，ctx 被取消时提前返回 true
+func wait(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return true
+	case <-timer.C:
+		return false
 	}
+}
 
-	return *result
+// selectAdapters 按选择条件挑出要重置的网卡，并返回选择说明
+func (r *AdapterRepairer) selectAdapters(ctx context.Context) ([]types.AdapterInfo, string, error) {
+	adapters, err := r.list(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sel := r.selection
+	if sel.Name != "" || sel.Index > 0 {
+		for _, adapter := range adapters {
+			if (sel.Name != "" && strings.EqualFold(adapter.Name, sel.Name)) || (sel.Index > 0 && adapter.Index == sel.Index) {
+				return []types.AdapterInfo{adapter}, "", nil
+			}
+		}
+		target := sel.Name
+		if target == "" {
+			target = "#" + strconv.Itoa(sel.Index)
+		}
+		return nil, "", fmt.Errorf("未找到网卡 %s", target)
+	}
+
+	var candidates, failing, primary []types.AdapterInfo
+	for _, adapter := range adapters {
+		if adapter.Virtual && !sel.IncludeVirtual {
+			continue
+		}
+		// 未连接或已禁用的网卡只在明确指定时重置，避免启用用户有意禁用的网卡
+		if adapter.Status != "Up" {
+			continue
+		}
+		candidates = append(candidates, adapter)
+		if adapter.IsFailing() {
+			failing = append(failing, adapter)
+		}
+		if adapter.DefaultRoute {
+			primary = append(primary, adapter)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, "", fmt.Errorf("未找到已连接的物理网卡")
+	}
+	switch {
+	case sel.All:
+		return candidates, "", nil
+	case len(failing) > 0:
+		// 只重置检测为异常的网卡，承载默认路由的网卡也异常时先重置它
+		sort.SliceStable(failing, func(i, j int) bool {
+			return failing[i].DefaultRoute && !failing[j].DefaultRoute
+		})
+		return failing, "", nil
+	case len(primary) > 0:
+		// 没有异常网卡时重置实际承载流量的默认路由网卡
+		return primary, "未发现异常网卡，", nil
+	default:
+		return candidates, "未发现异常网卡，", nil
+	}
+}
+
+// DryRun 预演：列出将被禁用后重新启用的网卡
+func (r *AdapterRepairer) DryRun(ctx context.Context) ([]types.PlannedChange, error) {
+	adapters, note, err := r.selectAdapters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	description := note + "禁用后立即重新启用，期间该网卡断网"
+	if r.pause > 0 {
+		description = note + fmt.Sprintf("禁用后等待 %g 秒重新启用，期间该网卡断网", r.pause.Seconds())
+	}
+
+	changes := make([]types.PlannedChange, 0, len(adapters))
+	for _, adapter := range adapters {
+		changes = append(changes, types.PlannedChange{
+			Kind:        types.ChangeAdapter,
+			Target:      adapter.Name,
+			Description: description,
+		})
+	}
+	return changes, nil
 }
@@ -0,0 +1,169 @@
+package repair
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"network-rescue-toolkit/pkg/executor"
+	"network-rescue-toolkit/pkg/types"
+)
+
+// sampleAdapters 一块正常的有线网卡、一块没拿到地址的 Wi-Fi 网卡、一块没拿到地址的 Hyper-V 虚拟网卡和一块未插网线的有线网卡
+var sampleAdapters = []types.AdapterInfo{
+	{Index: 4, Name: "Ethernet 2", Status: "Up", IPAddresses: []string{"192.168.1.20"}},
+	{Index: 7, Name: "WLAN", Status: "Up", IPAddresses: []string{"169.254.10.2"}},
+	{Index: 12, Name: "vEthernet (Default Switch)", Status: "Up", Virtual: true},
+	{Index: 15, Name: "以太网", Status: "Down"},
+}
+
+// newTestAdapterRepairer 创建使用样本网卡、不等待的网络适配器修复器
+func newTestAdapterRepairer(fake *executor.FakeRunner, selection AdapterSelection) *AdapterRepairer {
+	r := newAdapterRepairerWithLister(fake, func(ctx context.Context) ([]types.AdapterInfo, error) {
+		return sampleAdapters, nil
+	})
+	r.pause = 0
+	return r.WithSelection(selection)
+}
+
+// resetAdapters 返回被禁用后重新启用的网卡
+func resetAdapters(fake *executor.FakeRunner) []string {
+	var names []string
+	for _, call := range fake.Calls() {
+		if name, ok := strings.CutSuffix(call, " enable"); ok {
+			names = append(names, strings.TrimPrefix(name, "netsh interface set interface "))
+		}
+	}
+	return names
+}
+
+func TestAdapterRepairerSelection(t *testing.T) {
+	tests := []struct {
+		name      string
+		selection AdapterSelection
+		want      []string
+	}{
+		{"没有默认路由时重置没拿到地址的物理网卡", AdapterSelection{}, []string{"WLAN"}},
+		{"全部物理网卡", AdapterSelection{All: true}, []string{"Ethernet 2", "WLAN"}},
+		{"包含虚拟网卡", AdapterSelection{IncludeVirtual: true}, []string{"WLAN", "vEthernet (Default Switch)"}},
+		{"按名称指定", AdapterSelection{Name: "ethernet 2"}, []string{"Ethernet 2"}},
+		{"按索引指定虚拟网卡", AdapterSelection{Index: 12}, []string{"vEthernet (Default Switch)"}},
+		{"按名称指定未连接的网卡", AdapterSelection{Name: "以太网"}, []string{"以太网"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := executor.NewFakeRunner()
+			fake.SetDefault(executor.CommandResult{})
+
+			result := newTestAdapterRepairer(fake, tt.selection).Repair(context.Background())
+			if !result.Success {
+				t.Fatalf("修复失败: %s", result.Message)
+			}
+			if got := resetAdapters(fake); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("重置的网卡为 %v，期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdapterRepairerReportsFailures(t *testing.T) {
+	fake := executor.NewFakeRunner().OnFailure("netsh interface set interface WLAN enable", 1, "拒绝访问")
+	fake.SetDefault(executor.CommandResult{})
+
+	result := newTestAdapterRepairer(fake, AdapterSelection{All: true}).Repair(context.Background())
+	if result.Success || !strings.Contains(result.Message, "WLAN") || strings.Contains(result.Message, "Ethernet 2") {
+		t.Errorf("应只报告重新启用失败的网卡: %+v", result)
+	}
+
+	result = newTestAdapterRepairer(fake, AdapterSelection{Name: "以太网 3"}).Repair(context.Background())
+	if result.Success || result.Message != "未找到网卡 以太网 3" {
+		t.Errorf("指定的网卡不存在时应失败: %+v", result)
+	}
+}
+
+func TestAdapterRepairerDryRun(t *testing.T) {
+	fake := executor.NewFakeRunner()
+	changes, err := newTestAdapterRepairer(fake, AdapterSelection{}).DryRun(context.Background())
+	if err != nil {
+		t.Fatalf("预演失败: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Kind != types.ChangeAdapter || changes[0].Target != "WLAN" {
+		t.Errorf("预演应只列出没拿到地址的物理网卡: %+v", changes)
+	}
+	if len(fake.Calls()) != 0 {
+		t.Errorf("预演不应执行命令: %v", fake.Calls())
+	}
+	if !strings.Contains(changes[0].Description, "立即重新启用") {
+		t.Errorf("不等待时不应描述等待时间: %q", changes[0].Description)
+	}
+
+	r := newTestAdapterRepairer(fake, AdapterSelection{})
+	r.pause = 1500 * time.Millisecond
+	changes, _ = r.DryRun(context.Background())
+	if len(changes) != 1 || !strings.Contains(changes[0].Description, "等待 1.5 秒") {
+		t.Errorf("预演应按实际等待时间描述: %+v", changes)
+	}
+}
+
+// repairAdaptersFrom 使用指定网卡运行修复，返回重置的网卡
+func repairAdaptersFrom(t *testing.T, adapters []types.AdapterInfo) []string {
+	t.Helper()
+	fake := executor.NewFakeRunner()
+	fake.SetDefault(executor.CommandResult{})
+	r := newAdapterRepairerWithLister(fake, func(ctx context.Context) ([]types.AdapterInfo, error) {
+		return adapters, nil
+	})
+	r.pause = 0
+
+	if result := r.Repair(context.Background()); !result.Success {
+		t.Fatalf("修复失败: %s", result.Message)
+	}
+	return resetAdapters(fake)
+}
+
+func TestAdapterRepairerSkipsHealthyDefaultRoute(t *testing.T) {
+	got := repairAdaptersFrom(t, []types.AdapterInfo{
+		{Index: 7, Name: "WLAN", Status: "Up", IPAddresses: []string{"192.168.0.20"}, DefaultRoute: true},
+		{Index: 4, Name: "以太网", Status: "Up", IPAddresses: []string{"169.254.3.4"}},
+	})
+	if len(got) != 1 || got[0] != "以太网" {
+		t.Errorf("应只重置异常的网卡，不应重置正常的默认路由网卡: %v", got)
+	}
+}
+
+func TestAdapterRepairerResetsFailingDefaultRouteFirst(t *testing.T) {
+	got := repairAdaptersFrom(t, []types.AdapterInfo{
+		{Index: 4, Name: "以太网", Status: "Up", IPAddresses: []string{"169.254.3.4"}},
+		{Index: 7, Name: "WLAN", Status: "Up", IPAddresses: []string{"169.254.8.9"}, DefaultRoute: true},
+	})
+	if strings.Join(got, ",") != "WLAN,以太网" {
+		t.Errorf("承载默认路由的异常网卡应最先重置: %v", got)
+	}
+}
+
+func TestAdapterRepairerFallsBackToDefaultRoute(t *testing.T) {
+	got := repairAdaptersFrom(t, []types.AdapterInfo{
+		{Index: 4, Name: "以太网", Status: "Up", IPAddresses: []string{"192.168.1.20"}},
+		{Index: 7, Name: "WLAN", Status: "Up", IPAddresses: []string{"192.168.0.20"}, DefaultRoute: true},
+	})
+	if len(got) != 1 || got[0] != "WLAN" {
+		t.Errorf("没有异常网卡时应重置承载默认路由的网卡: %v", got)
+	}
+}
+
+func TestAdapterRepairerCancelReenablesAdapter(t *testing.T) {
+	fake := executor.NewFakeRunner()
+	fake.SetDefault(executor.CommandResult{})
+	r := newTestAdapterRepairer(fake, AdapterSelection{All: true})
+	r.pause = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	result := r.Repair(ctx)
+	if got := resetAdapters(fake); result.Success || len(got) != 1 || got[0] != "Ethernet 2" {
+		t.Errorf("取消时应重新启用已禁用的网卡并停止后续操作: %v %+v", got, result)
+	}
+}
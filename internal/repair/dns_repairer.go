@@ -10,13 +10,13 @@ import (
 
 // DNSRepairer DNS 修复器
 type DNSRepairer struct {
-	executor *executor.CommandExecutor
+	executor executor.Runner
 }
 
 // NewDNSRepairer 创建 DNS 修复器
-func NewDNSRepairer() *DNSRepairer {
+func NewDNSRepairer(runner executor.Runner) *DNSRepairer {
 	return &DNSRepairer{
-		executor: executor.NewCommandExecutor(),
+		executor: runner,
 	}
 }
 
@@ -35,13 +35,33 @@ func (r *DNSRepairer) RequiresAdmin() bool {
 	return false
 }
 
+// ModifiesConfig 是否会修改系统网络配置
+func (r *DNSRepairer) ModifiesConfig() bool {
+	return false
+}
+
+// Targets 返回该修复器能解决的诊断项
+func (r *DNSRepairer) Targets() []string {
+	return []string{"dns", "connectivity"}
+}
+
+// Invasiveness 影响程度（只刷新 DNS 缓存）
+func (r *DNSRepairer) Invasiveness() int {
+	return 30
+}
+
+// VerifiedBy 返回修复后用于验证的诊断项
+func (r *DNSRepairer) VerifiedBy() []string {
+	return []string{"dns"}
+}
+
 // Repair 执行修复
 func (r *DNSRepairer) Repair(ctx context.Context) types.RepairResult {
 	result := types.NewRepairResult(r.ID(), r.Name())
 	result.Timestamp = time.Now()
 
 	// 执行 ipconfig /flushdns
-	cmdResult := r.executor.ExecuteIPConfig(ctx, "/flushdns")
+	cmdResult := r.executor.Execute(ctx, "ipconfig", "/flushdns")
 
 	if cmdResult.IsSuccess() {
 		result.SetSuccess("DNS 缓存已刷新")
@@ -51,3 +71,10 @@ func (r *DNSRepairer) Repair(ctx context.Context) types.RepairResult {
 
 	return *result
 }
+
+// DryRun 预演
+func (r *DNSRepairer) DryRun(ctx context.Context) ([]types.PlannedChange, error) {
+	return []types.PlannedChange{
+		commandChange("清除本地 DNS 缓存", "ipconfig", "/flushdns"),
+	}, nil
+}
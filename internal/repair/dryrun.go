@@ -0,0 +1,91 @@
+package repair
+
+import (
+	"context"
+	"strings"
+
+	"network-rescue-toolkit/pkg/backup"
+	"network-rescue-toolkit/pkg/types"
+)
+
+// DryRunner 支持预演的修复器（可选接口），预演只读取当前状态，不做任何修改
+type DryRunner interface {
+	// DryRun 返回修复时将要进行的修改
+	DryRun(ctx context.Context) ([]types.PlannedChange, error)
+}
+
+// DryRun 预演单个修复操作，不执行任何命令、不创建快照
+func (e *Engine) DryRun(ctx context.Context, id string) types.DryRunResult {
+	repairer := e.find(id)
+	if repairer == nil {
+		return types.DryRunResult{ID: id, Name: "未知修复项", Changes: []types.PlannedChange{}, Error: "未找到指定的修复项"}
+	}
+	return e.dryRun(ctx, repairer, repairer.ModifiesConfig())
+}
+
+// DryRunAll 预演综合修复，与 RepairAll 执行的修复器一致
+func (e *Engine) DryRunAll(ctx context.Context) []types.DryRunResult {
+	e.mu.RLock()
+	repairers := append([]Repairer(nil), e.repairers...)
+	e.mu.RUnlock()
+
+	results := make([]types.DryRunResult, 0, len(repairers))
+	snapshotted := false
+	for _, repairer := range repairers {
+		if isOnDemand(repairer) {
+			continue
+		}
+		// 综合修复只在第一个修改配置的修复器前创建一次快照
+		snapshot := repairer.ModifiesConfig() && !snapshotted
+		snapshotted = snapshotted || snapshot
+		results = append(results, e.dryRun(ctx, repairer, snapshot))
+	}
+	return results
+}
+
+// dryRun 收集修复器的预演结果
+func (e *Engine) dryRun(ctx context.Context, r Repairer, snapshot bool) types.DryRunResult {
+	e.mu.RLock()
+	hasBackup := e.backupManager != nil
+	e.mu.RUnlock()
+
+	result := types.DryRunResult{
+		ID:             r.ID(),
+		Name:           r.Name(),
+		RequiresAdmin:  r.RequiresAdmin(),
+		CreateSnapshot: snapshot && hasBackup,
+		Changes:        []types.PlannedChange{},
+	}
+
+	dryRunner, ok := r.(DryRunner)
+	if !ok {
+		result.Error = "该修复项不支持预演"
+		return result
+	}
+	changes, err := dryRunner.DryRun(ctx)
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if changes != nil {
+		result.Changes = changes
+	}
+	return result
+}
+
+// commandChange 描述将要执行的命令
+func commandChange(description, name string, args ...string) types.PlannedChange {
+	return types.PlannedChange{
+		Kind:        types.ChangeCommand,
+		Target:      strings.Join(append([]string{name}, args...), " "),
+		Description: description,
+	}
+}
+
+// lineDiff 生成逐行差异文本（"-" 为删除的行，"+" 为新增的行，相同的行省略）
+func lineDiff(oldText, newText string) string {
+	var b strings.Builder
+	for _, change := range backup.DiffLines(backup.SplitLines(oldText), backup.SplitLines(newText)) {
+		b.WriteString(change.String() + "\n")
+	}
+	return b.String()
+}
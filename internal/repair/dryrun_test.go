@@ -0,0 +1,76 @@
+package repair
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"network-rescue-toolkit/pkg/registry"
+	"network-rescue-toolkit/pkg/types"
+)
+
+func TestDryRunAllExecutesNothing(t *testing.T) {
+	e, fake, store := newTestEngine(t)
+
+	results := e.DryRunAll(context.Background())
+	if len(results) != 5 {
+		t.Fatalf("期望 5 个预演结果，实际 %d", len(results))
+	}
+	if calls := fake.Calls(); len(calls) != 0 {
+		t.Errorf("预演不应执行任何命令: %v", calls)
+	}
+	if enabled, _ := store.ReadDWORD(registry.ProxySettingsPath, "ProxyEnable"); enabled != 1 {
+		t.Error("预演不应修改注册表")
+	}
+
+	winsock := results[0]
+	if !winsock.CreateSnapshot || len(winsock.Changes) != 1 || winsock.Changes[0].Target != "netsh winsock reset" {
+		t.Errorf("Winsock 预演结果不符: %+v", winsock)
+	}
+	if results[1].CreateSnapshot {
+		t.Error("综合修复只在第一个修改配置的修复器前创建快照")
+	}
+
+	proxy := results[4]
+	if len(proxy.Changes) != 1 {
+		t.Fatalf("代理预演应包含 1 项修改: %+v", proxy)
+	}
+	if change := proxy.Changes[0]; change.Kind != types.ChangeRegistry || change.OldValue != "1" || change.NewValue != "0" {
+		t.Errorf("代理预演应给出注册表的新旧值: %+v", change)
+	}
+}
+
+func TestDryRunProxyAlreadyDisabled(t *testing.T) {
+	store := registry.NewMemoryStore()
+	store.SetDWORD(registry.ProxySettingsPath, "ProxyEnable", 0)
+
+	changes, err := NewProxyRepairer(store).DryRun(context.Background())
+	if err != nil || len(changes) != 0 {
+		t.Errorf("代理已关闭时无需修改: %v %+v", err, changes)
+	}
+}
+
+func TestDryRunHostsDiff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts")
+	os.WriteFile(path, []byte(strings.Replace(defaultHosts, "# localhost name", "1.2.3.4 www.baidu.com\n# localhost name", 1)), 0644)
+
+	changes, err := newHostsRepairerWithPath(path).DryRun(context.Background())
+	if err != nil || len(changes) != 1 {
+		t.Fatalf("预演失败: %v %+v", err, changes)
+	}
+	if changes[0].Diff != "- 1.2.3.4 www.baidu.com\n" {
+		t.Errorf("差异应只包含被删除的劫持条目: %q", changes[0].Diff)
+	}
+	if content, _ := os.ReadFile(path); !strings.Contains(string(content), "www.baidu.com") {
+		t.Error("预演不应改写 HOSTS 文件")
+	}
+}
+
+func TestDryRunUnknownID(t *testing.T) {
+	e := newEmptyEngine()
+	if result := e.DryRun(context.Background(), "missing"); result.Error == "" {
+		t.Errorf("未知修复项应返回错误: %+v", result)
+	}
+}
@@ -2,19 +2,53 @@ package repair
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
+	"network-rescue-toolkit/pkg/audit"
+	"network-rescue-toolkit/pkg/backup"
 	"network-rescue-toolkit/pkg/types"
 )
 
+// defaultHosts Windows 默认的 HOSTS 文件内容
+const defaultHosts = `# Copyright (c) 1993-2009 Microsoft Corp.
+#
+# This is a sample HOSTS file used by Microsoft TCP/IP for Windows.
+#
+# This file contains the mappings of IP addresses to host names. Each
+# entry should be kept on an individual line. The IP address should
+# be placed in the first column followed by the corresponding host name.
+# The IP address and the host name should be separated by at least one
+# space.
+#
+# Additionally, comments (such as these) may be inserted on individual
+# lines or following the machine name denoted by a '#' symbol.
+#
+# For example:
+#
+#      102.54.94.97     rhino.acme.com          # source server
+#       38.25.63.10     x.acme.com              # x client host
+
+# localhost name resolution is handled within DNS itself.
+#	127.0.0.1       localhost
+#	::1             localhost
+`
+
 // HostsRepairer HOSTS 修复器
-type HostsRepairer struct{}
+type HostsRepairer struct {
+	path string
+}
 
 // NewHostsRepairer 创建 HOSTS 修复器
 func NewHostsRepairer() *HostsRepairer {
-	return &HostsRepairer{}
+	return newHostsRepairerWithPath(filepath.Join(os.Getenv("SystemRoot"), "System32", "drivers", "etc", "hosts"))
+}
+
+// newHostsRepairerWithPath 创建修复指定 HOSTS 文件的修复器
+func newHostsRepairerWithPath(path string) *HostsRepairer {
+	return &HostsRepairer{path: path}
 }
 
 // ID 返回修复器 ID
@@ -32,58 +66,84 @@ func (r *HostsRepairer) RequiresAdmin() bool {
 	return true
 }
 
+// ModifiesConfig 是否会修改系统网络配置
+func (r *HostsRepairer) ModifiesConfig() bool {
+	return true
+}
+
+// Targets 返回该修复器能解决的诊断项
+func (r *HostsRepairer) Targets() []string {
+	return []string{"hosts"}
+}
+
+// Invasiveness 影响程度（只还原 HOSTS 文件）
+func (r *HostsRepairer) Invasiveness() int {
+	return 20
+}
+
+// VerifiedBy 返回修复后用于验证的诊断项
+func (r *HostsRepairer) VerifiedBy() []string {
+	return []string{"hosts"}
+}
+
 // Repair 执行修复
 func (r *HostsRepairer) Repair(ctx context.Context) types.RepairResult {
 	result := types.NewRepairResult(r.ID(), r.Name())
 	result.Timestamp = time.Now()
 
-	hostsPath := filepath.Join(os.Getenv("SystemRoot"), "System32", "drivers", "etc", "hosts")
-	backupPath := hostsPath + ".backup"
-
-	// 备份当前文件
-	content, err := os.ReadFile(hostsPath)
+	// 写入默认内容（原内容已由引擎在修复前的快照中保存）
+	start := time.Now()
+	err := os.WriteFile(r.path, []byte(defaultHosts), 0644)
+	audit.Default().LogWrite(ctx, audit.ActionFileWrite, r.path, nil, start, err)
 	if err != nil {
-		result.SetFailure("无法读取 HOSTS 文件: " + err.Error())
+		result.SetFailure("无法写入 HOSTS 文件: " + err.Error())
 		return *result
 	}
 
-	err = os.WriteFile(backupPath, content, 0644)
-	if err != nil {
-		result.SetFailure("无法备份 HOSTS 文件: " + err.Error())
-		return *result
-	}
-	result.SetBackupPath(backupPath)
+	result.SetSuccess("HOSTS 文件已恢复为默认状态")
+	return *result
+}
 
-	// 写入默认内容
-	defaultHosts := `# Copyright (c) 1993-2009 Microsoft Corp.
-#
-# This is a sample HOSTS file used by Microsoft TCP/IP for Windows.
-#
-# This file contains the mappings of IP addresses to host names. Each
-# entry should be kept on an individual line. The IP address should
-# be placed in the first column followed by the corresponding host name.
-# The IP address and the host name should be separated by at least one
-# space.
-#
-# Additionally, comments (such as these) may be inserted on individual
-# lines or following the machine name denoted by a '#' symbol.
-#
-# For example:
-#
-#      102.54.94.97     rhino.acme.com          # source server
-#       38.25.63.10     x.acme.com              # x client host
+// DryRun 预演：与默认内容逐行比较
+func (r *HostsRepairer) DryRun(ctx context.Context) ([]types.PlannedChange, error) {
+	content, err := os.ReadFile(r.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("读取 HOSTS 文件失败: %w", err)
+	}
 
-# localhost name resolution is handled within DNS itself.
-#	127.0.0.1       localhost
-#	::1             localhost
-`
+	diff := lineDiff(string(content), defaultHosts)
+	if diff == "" {
+		return nil, nil
+	}
+	return []types.PlannedChange{{
+		Kind:        types.ChangeFile,
+		Target:      r.path,
+		Description: "HOSTS 文件恢复为默认内容",
+		Diff:        diff,
+	}}, nil
+}
 
-	err = os.WriteFile(hostsPath, []byte(defaultHosts), 0644)
+// Capture 保存修复前的 HOSTS 文件内容；文件原本不存在时撤销会将其删除
+func (r *HostsRepairer) Capture(ctx context.Context) (Rollback, error) {
+	content, err := os.ReadFile(r.path)
+	if os.IsNotExist(err) {
+		return func(ctx context.Context) error {
+			start := time.Now()
+			err := os.Remove(r.path)
+			if os.IsNotExist(err) {
+				err = nil
+			}
+			audit.Default().LogWrite(ctx, audit.ActionFileWrite, r.path, []string{"remove"}, start, err)
+			if err != nil {
+				return fmt.Errorf("删除 HOSTS 文件失败: %w", err)
+			}
+			return nil
+		}, nil
+	}
 	if err != nil {
-		result.SetFailure("无法写入 HOSTS 文件: " + err.Error())
-		return *result
+		return nil, fmt.Errorf("读取 HOSTS 文件失败: %w", err)
 	}
-
-	result.SetSuccess("HOSTS 文件已恢复为默认状态")
-	return *result
+	return func(ctx context.Context) error {
+		return backup.NewFileHostsApplier(r.path).ApplyHosts(ctx, string(content))
+	}, nil
 }
@@ -10,13 +10,13 @@ import (
 
 // IPRepairer IP 修复器
 type IPRepairer struct {
-	executor *executor.CommandExecutor
+	executor executor.Runner
 }
 
 // NewIPRepairer 创建 IP 修复器
-func NewIPRepairer() *IPRepairer {
+func NewIPRepairer(runner executor.Runner) *IPRepairer {
 	return &IPRepairer{
-		executor: executor.NewCommandExecutor(),
+		executor: runner,
 	}
 }
 
@@ -35,13 +35,33 @@ func (r *IPRepairer) RequiresAdmin() bool {
 	return true
 }
 
+// ModifiesConfig 是否会修改系统网络配置
+func (r *IPRepairer) ModifiesConfig() bool {
+	return true
+}
+
+// Targets 返回该修复器能解决的诊断项
+func (r *IPRepairer) Targets() []string {
+	return []string{"ip", "gateway", "dns", "connectivity"}
+}
+
+// Invasiveness 影响程度（重新获取 IP 会短暂断网）
+func (r *IPRepairer) Invasiveness() int {
+	return 50
+}
+
+// VerifiedBy 返回修复后用于验证的诊断项
+func (r *IPRepairer) VerifiedBy() []string {
+	return []string{"ip"}
+}
+
 // Repair 执行修复
 func (r *IPRepairer) Repair(ctx context.Context) types.RepairResult {
 	result := types.NewRepairResult(r.ID(), r.Name())
 	result.Timestamp = time.Now()
 
 	// 执行 ipconfig /release
-	releaseResult := r.executor.ExecuteIPConfig(ctx, "/release")
+	releaseResult := r.executor.Execute(ctx, "ipconfig", "/release")
 	if !releaseResult.IsSuccess() {
 		result.SetFailure("IP 释放失败: " + releaseResult.Stderr)
 		return *result
@@ -51,7 +71,7 @@ func (r *IPRepairer) Repair(ctx context.Context) types.RepairResult {
 	time.Sleep(time.Second)
 
 	// 执行 ipconfig /renew
-	renewResult := r.executor.ExecuteIPConfig(ctx, "/renew")
+	renewResult := r.executor.Execute(ctx, "ipconfig", "/renew")
 	if !renewResult.IsSuccess() {
 		result.SetFailure("IP 续租失败: " + renewResult.Stderr)
 		return *result
@@ -60,3 +80,11 @@ func (r *IPRepairer) Repair(ctx context.Context) types.RepairResult {
 	result.SetSuccess("IP 地址已重新获取")
 	return *result
 }
+
+// DryRun 预演
+func (r *IPRepairer) DryRun(ctx context.Context) ([]types.PlannedChange, error) {
+	return []types.PlannedChange{
+		commandChange("释放所有网卡的 DHCP 地址（期间断网）", "ipconfig", "/release"),
+		commandChange("重新获取 DHCP 地址", "ipconfig", "/renew"),
+	}, nil
+}
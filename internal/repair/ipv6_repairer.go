@@ -0,0 +1,109 @@
+package repair
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"network-rescue-toolkit/pkg/executor"
+	"network-rescue-toolkit/pkg/netif"
+	"network-rescue-toolkit/pkg/types"
+)
+
+// IPv6Repairer IPv6 故障修复器
+// 通过调整前缀策略让系统优先使用 IPv4，IPv6 不通时不再等待超时；不会禁用 IPv6
+type IPv6Repairer struct {
+	executor executor.Runner
+}
+
+// NewIPv6Repairer 创建 IPv6 故障修复器
+func NewIPv6Repairer(runner executor.Runner) *IPv6Repairer {
+	return &IPv6Repairer{
+		executor: runner,
+	}
+}
+
+// ID 返回修复器 ID
+func (r *IPv6Repairer) ID() string {
+	return "ipv6"
+}
+
+// Name 返回修复器名称
+func (r *IPv6Repairer) Name() string {
+	return "优先使用 IPv4"
+}
+
+// RequiresAdmin 是否需要管理员权限
+func (r *IPv6Repairer) RequiresAdmin() bool {
+	return true
+}
+
+// ModifiesConfig 是否会修改系统网络配置
+func (r *IPv6Repairer) ModifiesConfig() bool {
+	return true
+}
+
+// Targets 返回该修复器能解决的诊断项
+func (r *IPv6Repairer) Targets() []string {
+	return []string{"ipv6"}
+}
+
+// Invasiveness 影响程度（只调整地址优先级）
+func (r *IPv6Repairer) Invasiveness() int {
+	return 40
+}
+
+// VerifiedBy 返回修复后用于验证的诊断项
+func (r *IPv6Repairer) VerifiedBy() []string {
+	return []string{"ipv6"}
+}
+
+// OnDemand 只在 IPv6 检测报告故障时单独执行，综合修复不会执行
+func (r *IPv6Repairer) OnDemand() bool {
+	return true
+}
+
+// Repair 执行修复
+func (r *IPv6Repairer) Repair(ctx context.Context) types.RepairResult {
+	result := types.NewRepairResult(r.ID(), r.Name())
+	result.Timestamp = time.Now()
+
+	// IPv4 映射地址 ::ffff:0:0/96 的优先级默认为 35，低于 ::/0 的 40；提高到 100 即优先 IPv4
+	cmdResult := r.executor.Execute(ctx, "netsh", "interface", "ipv6", "set", "prefixpolicy", netif.IPv4MappedPrefix, "100", "4")
+
+	if cmdResult.IsSuccess() {
+		result.SetSuccess("已设置为优先使用 IPv4，IPv6 故障时不再等待超时")
+	} else {
+		result.SetFailure("设置前缀策略失败: " + cmdResult.Stderr)
+	}
+
+	return *result
+}
+
+// DryRun 预演
+func (r *IPv6Repairer) DryRun(ctx context.Context) ([]types.PlannedChange, error) {
+	return []types.PlannedChange{
+		commandChange("将 IPv4 映射地址的前缀策略优先级改为 100", "netsh", "interface", "ipv6", "set", "prefixpolicy", netif.IPv4MappedPrefix, "100", "4"),
+	}, nil
+}
+
+// Capture 保存修复前 IPv4 映射地址的前缀策略，撤销时恢复原优先级；原本没有该策略时撤销会将其删除
+func (r *IPv6Repairer) Capture(ctx context.Context) (Rollback, error) {
+	cmdResult := r.executor.Execute(ctx, "netsh", "interface", "ipv6", "show", "prefixpolicies")
+	if !cmdResult.IsSuccess() {
+		return nil, fmt.Errorf("读取前缀策略失败: %s", cmdResult.Stderr)
+	}
+
+	policy, found := netif.FindPrefixPolicy(netif.ParsePrefixPolicies(cmdResult.Stdout), netif.IPv4MappedPrefix)
+	args := []string{"interface", "ipv6", "delete", "prefixpolicy", netif.IPv4MappedPrefix}
+	if found {
+		args = []string{"interface", "ipv6", "set", "prefixpolicy", netif.IPv4MappedPrefix, strconv.Itoa(policy.Precedence), strconv.Itoa(policy.Label)}
+	}
+	return func(ctx context.Context) error {
+		if result := r.executor.Execute(ctx, "netsh", args...); !result.IsSuccess() {
+			return fmt.Errorf("恢复前缀策略失败: %s", result.Stderr)
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,225 @@
+package repair
+
+import (
+	"context"
+	"sort"
+
+	"network-rescue-toolkit/pkg/types"
+)
+
+// CheckFunc 重新运行指定的诊断项
+type CheckFunc func(ctx context.Context, checkerID string) types.DiagnosticResult
+
+// Plan 根据诊断结果生成智能修复计划
+// 只选择能解决实际失败项的修复器，自身诊断项已通过的修复器不再执行，步骤按影响程度从小到大排列
+func (e *Engine) Plan(results []types.DiagnosticResult) types.RepairPlan {
+	plan := types.RepairPlan{
+		Failing: make([]string, 0),
+		Steps:   make([]types.RepairPlanStep, 0),
+	}
+
+	failed := make(map[string]bool)  // 实际检查失败的诊断项
+	skipped := make(map[string]bool) // 因依赖失败未执行的诊断项
+	passed := make(map[string]bool)
+	for i := range results {
+		r := &results[i]
+		switch {
+		case r.Status == types.StatusSkipped:
+			skipped[r.ID] = true
+		case r.NeedsRepair():
+			failed[r.ID] = true
+		default:
+			passed[r.ID] = true
+			continue
+		}
+		plan.Failing = append(plan.Failing, r.ID)
+	}
+
+	e.mu.RLock()
+	repairers := append([]Repairer(nil), e.repairers...)
+	e.mu.RUnlock()
+
+	for _, r := range repairers {
+		if passed[r.ID()] {
+			continue
+		}
+
+		var targets []string
+		addressesFailure := false
+		for _, id := range targetsOf(r) {
+			if failed[id] {
+				addressesFailure = true
+				targets = append(targets, id)
+			} else if skipped[id] {
+				targets = append(targets, id)
+			}
+		}
+		// 只针对未执行的诊断项时先不修复，等依赖的问题解决后再看
+		if !addressesFailure {
+			continue
+		}
+
+		plan.Steps = append(plan.Steps, types.RepairPlanStep{
+			RepairerID:    r.ID(),
+			Name:          r.Name(),
+			Targets:       targets,
+			Invasiveness:  invasivenessOf(r),
+			RequiresAdmin: r.RequiresAdmin(),
+		})
+	}
+
+	sort.SliceStable(plan.Steps, func(i, j int) bool {
+		return plan.Steps[i].Invasiveness < plan.Steps[j].Invasiveness
+	})
+	return plan
+}
+
+// RunPlan 按计划逐步修复，每一步后重新运行相关诊断项，全部恢复后立即停止
+// 在第一个会修改配置的步骤前创建一次快照；需要重启的步骤执行后停止，重启前的诊断结果不可信
+func (e *Engine) RunPlan(ctx context.Context, plan types.RepairPlan, recheck CheckFunc) types.RepairPlanResult {
+	outcome := types.RepairPlanResult{
+		Steps:     make([]types.RepairPlanStepResult, 0, len(plan.Steps)),
+		Remaining: make([]string, 0),
+	}
+
+	pending := make(map[string]bool, len(plan.Failing))
+	for _, id := range plan.Failing {
+		pending[id] = true
+	}
+
+	repairResults := make([]types.RepairResult, 0, len(plan.Steps))
+	backupPath := ""
+	for _, planStep := range plan.Steps {
+		if ctx.Err() != nil || len(pending) == 0 {
+			break
+		}
+
+		step := types.RepairPlanStepResult{RepairPlanStep: planStep, Rechecks: make([]types.DiagnosticResult, 0)}
+		targets := pendingOf(planStep.Targets, pending)
+		if len(targets) == 0 {
+			step.Skipped = true
+			outcome.Steps = append(outcome.Steps, step)
+			continue
+		}
+
+		repairer := e.find(planStep.RepairerID)
+		if repairer == nil {
+			// 计划中的修复项不存在时记录失败，其针对的诊断项留在未通过列表中
+			result := unknownRepairer(planStep.RepairerID)
+			step.Repair = &result
+			repairResults = append(repairResults, result)
+			outcome.Steps = append(outcome.Steps, step)
+			continue
+		}
+		if repairer.ModifiesConfig() && backupPath == "" {
+			path, err := e.snapshot("智能修复前快照")
+			if err != nil {
+				// 没有快照就不继续修改配置
+				result := snapshotFailure(repairer, err)
+				step.Repair = &result
+				outcome.Steps = append(outcome.Steps, step)
+				e.setResults(append(repairResults, result))
+				return finishPlan(outcome, plan.Failing, pending)
+			}
+			backupPath = path
+		}
+
+		result := e.run(ctx, repairer, backupPath)
+		step.Repair = &result
+		repairResults = append(repairResults, result)
+
+		if result.RequireReboot {
+			outcome.RequiresReboot = true
+			outcome.Steps = append(outcome.Steps, step)
+			break
+		}
+
+		// 先验证本步骤针对的诊断项，都恢复后再确认其余未通过的诊断项
+		// 修复后验证已运行过的诊断项直接使用其结果，不再重复运行
+		verified := verifiedAfter(result)
+		step.Rechecks = append(step.Rechecks, recheckAll(ctx, targets, pending, recheck, verified)...)
+		if !anyPending(targets, pending) {
+			step.Rechecks = append(step.Rechecks, recheckAll(ctx, pendingOf(plan.Failing, pending), pending, recheck, verified)...)
+		}
+		outcome.Steps = append(outcome.Steps, step)
+	}
+
+	e.setResults(repairResults)
+	return finishPlan(outcome, plan.Failing, pending)
+}
+
+// finishPlan 汇总仍未通过的诊断项
+func finishPlan(outcome types.RepairPlanResult, failing []string, pending map[string]bool) types.RepairPlanResult {
+	outcome.Remaining = append(outcome.Remaining, pendingOf(failing, pending)...)
+	outcome.Healthy = len(outcome.Remaining) == 0 && !outcome.RequiresReboot
+	return outcome
+}
+
+// find 按 ID 查找修复器
+func (e *Engine) find(id string) Repairer {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, r := range e.repairers {
+		if r.ID() == id {
+			return r
+		}
+	}
+	return nil
+}
+
+// setResults 记录最近一次修复结果
+func (e *Engine) setResults(results []types.RepairResult) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.results = results
+}
+
+// verifiedAfter 返回修复后验证得到的诊断结果（按诊断项 ID）
+func verifiedAfter(result types.RepairResult) map[string]types.DiagnosticResult {
+	verified := make(map[string]types.DiagnosticResult)
+	if result.Verification == nil {
+		return verified
+	}
+	for _, after := range result.Verification.After {
+		verified[after.ID] = after
+	}
+	return verified
+}
+
+// recheckAll 重新运行诊断项，通过的从 pending 中移除
+// known 中已有的结果直接使用（每个只用一次），不再运行诊断
+func recheckAll(ctx context.Context, ids []string, pending map[string]bool, recheck CheckFunc, known map[string]types.DiagnosticResult) []types.DiagnosticResult {
+	results := make([]types.DiagnosticResult, 0, len(ids))
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			break
+		}
+		result, ok := known[id]
+		if ok {
+			delete(known, id)
+		} else {
+			result = recheck(ctx, id)
+		}
+		results = append(results, result)
+		if !result.NeedsRepair() {
+			delete(pending, id)
+		}
+	}
+	return results
+}
+
+// pendingOf 返回 ids 中仍未通过的诊断项
+func pendingOf(ids []string, pending map[string]bool) []string {
+	var result []string
+	for _, id := range ids {
+		if pending[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// anyPending ids 中是否还有未通过的诊断项
+func anyPending(ids []string, pending map[string]bool) bool {
+	return len(pendingOf(ids, pending)) > 0
+}
@@ -0,0 +1,164 @@
+package repair
+
+import (
+	"context"
+	"testing"
+
+	"network-rescue-toolkit/pkg/registry"
+	"network-rescue-toolkit/pkg/types"
+)
+
+// diagnosticResult 构造指定状态的诊断结果
+func diagnosticResult(id string, status types.DiagnosticStatus) types.DiagnosticResult {
+	r := types.NewDiagnosticResult(id, id)
+	r.Status = status
+	r.Repairable = status != types.StatusOK
+	return *r
+}
+
+// fakeChecks 按调用次数依次返回预设状态的诊断函数，超出预设时返回正常
+type fakeChecks struct {
+	statuses map[string][]types.DiagnosticStatus
+	calls    []string
+}
+
+func (f *fakeChecks) check(ctx context.Context, id string) types.DiagnosticResult {
+	f.calls = append(f.calls, id)
+	status := types.StatusOK
+	if queue := f.statuses[id]; len(queue) > 0 {
+		status = queue[0]
+		f.statuses[id] = queue[1:]
+	}
+	return diagnosticResult(id, status)
+}
+
+func TestPlanOrdersMinimalRepairers(t *testing.T) {
+	e, _, _ := newTestEngine(t)
+
+	plan := e.Plan([]types.DiagnosticResult{
+		diagnosticResult("ip", types.StatusOK),
+		diagnosticResult("dns", types.StatusOK),
+		diagnosticResult("proxy", types.StatusWarning),
+		diagnosticResult("connectivity", types.StatusError),
+	})
+
+	want := []string{"proxy", "winsock", "tcpip"}
+	if len(plan.Steps) != len(want) {
+		t.Fatalf("期望修复步骤 %v，实际 %+v", want, plan.Steps)
+	}
+	for i, id := range want {
+		if plan.Steps[i].RepairerID != id {
+			t.Errorf("第 %d 步应为 %s，实际 %s", i+1, id, plan.Steps[i].RepairerID)
+		}
+	}
+	if len(plan.Failing) != 2 {
+		t.Errorf("未通过的诊断项应为 proxy 和 connectivity: %v", plan.Failing)
+	}
+}
+
+func TestRunPlanStopsWhenHealthy(t *testing.T) {
+	e, fake, store := newTestEngine(t)
+
+	plan := e.Plan([]types.DiagnosticResult{
+		diagnosticResult("dns", types.StatusOK),
+		diagnosticResult("proxy", types.StatusWarning),
+		diagnosticResult("connectivity", types.StatusError),
+	})
+	checks := &fakeChecks{statuses: map[string][]types.DiagnosticStatus{}}
+
+	outcome := e.RunPlan(context.Background(), plan, checks.check)
+	if !outcome.Healthy || len(outcome.Remaining) != 0 {
+		t.Errorf("清除代理后网络应恢复: %+v", outcome)
+	}
+	if len(outcome.Steps) != 1 || outcome.Steps[0].RepairerID != "proxy" || len(outcome.Steps[0].Rechecks) != 2 {
+		t.Errorf("应只执行代理修复并重新检查: %+v", outcome.Steps)
+	}
+	if fake.Called("netsh winsock reset") || fake.Called("netsh int ip reset") {
+		t.Error("网络恢复后不应继续执行需要重启的修复")
+	}
+	if enabled, _ := store.ReadDWORD(registry.ProxySettingsPath, "ProxyEnable"); enabled != 0 {
+		t.Error("代理应被关闭")
+	}
+	if outcome.Steps[0].Repair.BackupPath == "" {
+		t.Error("修改配置前应创建快照")
+	}
+}
+
+func TestRunPlanReusesVerification(t *testing.T) {
+	e, _, _ := newTestEngine(t)
+	checks := &fakeChecks{statuses: map[string][]types.DiagnosticStatus{"proxy": {types.StatusWarning}}}
+	e.SetVerifier(checks.check)
+
+	plan := e.Plan([]types.DiagnosticResult{
+		diagnosticResult("proxy", types.StatusWarning),
+		diagnosticResult("connectivity", types.StatusError),
+	})
+	outcome := e.RunPlan(context.Background(), plan, checks.check)
+	if !outcome.Healthy || len(outcome.Steps) != 1 || len(outcome.Steps[0].Rechecks) != 2 {
+		t.Fatalf("清除代理后网络应恢复: %+v", outcome)
+	}
+
+	counts := make(map[string]int)
+	for _, id := range checks.calls {
+		counts[id]++
+	}
+	if counts["proxy"] != 2 || counts["connectivity"] != 1 {
+		t.Errorf("修复前后验证过的诊断项不应再次运行: %v", checks.calls)
+	}
+}
+
+func TestRunPlanEscalatesAndStopsAtReboot(t *testing.T) {
+	e, fake, _ := newTestEngine(t)
+
+	plan := e.Plan([]types.DiagnosticResult{
+		diagnosticResult("ip", types.StatusOK),
+		diagnosticResult("dns", types.StatusError),
+		diagnosticResult("proxy", types.StatusOK),
+		diagnosticResult("connectivity", types.StatusError),
+	})
+	checks := &fakeChecks{statuses: map[string][]types.DiagnosticStatus{
+		"dns":          {types.StatusError},
+		"connectivity": {types.StatusError},
+	}}
+
+	outcome := e.RunPlan(context.Background(), plan, checks.check)
+	if outcome.Healthy || !outcome.RequiresReboot {
+		t.Errorf("重置 Winsock 后应提示重启: %+v", outcome)
+	}
+	if len(outcome.Steps) != 2 || outcome.Steps[0].RepairerID != "dns" || outcome.Steps[1].RepairerID != "winsock" {
+		t.Errorf("应先刷新 DNS，无效后再重置 Winsock: %+v", outcome.Steps)
+	}
+	if fake.Called("netsh int ip reset") {
+		t.Error("需要重启的修复执行后应停止")
+	}
+	if len(outcome.Remaining) != 2 {
+		t.Errorf("重启前诊断项仍未恢复: %v", outcome.Remaining)
+	}
+}
+
+func TestRunPlanReportsMissingRepairer(t *testing.T) {
+	e, _, _ := newTestEngine(t)
+	plan := types.RepairPlan{
+		Failing: []string{"proxy", "ipv6"},
+		Steps: []types.RepairPlanStep{
+			{RepairerID: "ipv6", Name: "IPv6", Targets: []string{"ipv6"}},
+			{RepairerID: "proxy", Name: "代理", Targets: []string{"proxy"}},
+		},
+	}
+	checks := &fakeChecks{statuses: map[string][]types.DiagnosticStatus{"ipv6": {types.StatusError}}}
+
+	outcome := e.RunPlan(context.Background(), plan, checks.check)
+	if len(outcome.Steps) != 2 {
+		t.Fatalf("不存在的修复项也应出现在步骤中: %+v", outcome.Steps)
+	}
+	missing := outcome.Steps[0].Repair
+	if missing == nil || missing.Success || missing.ID != "ipv6" || missing.Message != "未找到指定的修复项" {
+		t.Errorf("不存在的修复项应记录失败: %+v", missing)
+	}
+	if len(outcome.Remaining) != 1 || outcome.Remaining[0] != "ipv6" {
+		t.Errorf("未修复的诊断项应留在未通过列表中: %v", outcome.Remaining)
+	}
+	if results := e.GetResults(); len(results) != 2 || results[0].ID != "ipv6" {
+		t.Errorf("修复结果应包含失败的步骤: %+v", results)
+	}
+}
@@ -2,18 +2,27 @@ package repair
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
-	"golang.org/x/sys/windows/registry"
+	"network-rescue-toolkit/pkg/audit"
+	"network-rescue-toolkit/pkg/backup"
+	"network-rescue-toolkit/pkg/registry"
 	"network-rescue-toolkit/pkg/types"
 )
 
 // ProxyRepairer 代理修复器
-type ProxyRepairer struct{}
+type ProxyRepairer struct {
+	store registry.Store
+}
 
 // NewProxyRepairer 创建代理修复器
-func NewProxyRepairer() *ProxyRepairer {
-	return &ProxyRepairer{}
+func NewProxyRepairer(store registry.Store) *ProxyRepairer {
+	return &ProxyRepairer{
+		store: store,
+	}
 }
 
 // ID 返回修复器 ID
@@ -31,29 +40,42 @@ func (r *ProxyRepairer) RequiresAdmin() bool {
 	return false
 }
 
+// ModifiesConfig 是否会修改系统网络配置
+func (r *ProxyRepairer) ModifiesConfig() bool {
+	return true
+}
+
+// Targets 返回该修复器能解决的诊断项
+func (r *ProxyRepairer) Targets() []string {
+	return []string{"proxy", "connectivity"}
+}
+
+// Invasiveness 影响程度（只清除代理设置）
+func (r *ProxyRepairer) Invasiveness() int {
+	return 10
+}
+
+// VerifiedBy 返回修复后用于验证的诊断项
+func (r *ProxyRepairer) VerifiedBy() []string {
+	return []string{"proxy"}
+}
+
 // Repair 执行修复
 func (r *ProxyRepairer) Repair(ctx context.Context) types.RepairResult {
 	result := types.NewRepairResult(r.ID(), r.Name())
 	result.Timestamp = time.Now()
 
-	key, err := registry.OpenKey(registry.CURRENT_USER,
-		`Software\Microsoft\Windows\CurrentVersion\Internet Settings`,
-		registry.SET_VALUE)
-	if err != nil {
-		result.SetFailure("无法打开注册表: " + err.Error())
-		return *result
-	}
-	defer key.Close()
-
 	// 禁用代理
-	err = key.SetDWordValue("ProxyEnable", 0)
+	start := time.Now()
+	err := r.store.WriteDWORD(registry.ProxySettingsPath, "ProxyEnable", 0)
+	audit.Default().LogWrite(ctx, audit.ActionRegistryWrite, registry.ProxySettingsPath+`\ProxyEnable`, []string{"0"}, start, err)
 	if err != nil {
 		result.SetFailure("无法禁用代理: " + err.Error())
 		return *result
 	}
 
 	// 验证修改
-	proxyEnable, _, err := key.GetIntegerValue("ProxyEnable")
+	proxyEnable, err := r.store.ReadDWORD(registry.ProxySettingsPath, "ProxyEnable")
 	if err != nil || proxyEnable != 0 {
 		result.SetFailure("代理设置验证失败")
 		return *result
@@ -62,3 +84,36 @@ func (r *ProxyRepairer) Repair(ctx context.Context) types.RepairResult {
 	result.SetSuccess("代理设置已清除")
 	return *result
 }
+
+// DryRun 预演：读取当前的 ProxyEnable，已关闭时无需修改
+func (r *ProxyRepairer) DryRun(ctx context.Context) ([]types.PlannedChange, error) {
+	oldValue := "（未设置）"
+	current, err := r.store.ReadDWORD(registry.ProxySettingsPath, "ProxyEnable")
+	switch {
+	case err == nil && current == 0:
+		return nil, nil
+	case err == nil:
+		oldValue = strconv.FormatUint(uint64(current), 10)
+	case !errors.Is(err, registry.ErrValueNotFound):
+		return nil, fmt.Errorf("读取代理设置失败: %w", err)
+	}
+
+	return []types.PlannedChange{{
+		Kind:        types.ChangeRegistry,
+		Target:      registry.ProxySettingsPath + `\ProxyEnable`,
+		Description: "关闭系统代理",
+		OldValue:    oldValue,
+		NewValue:    "0",
+	}}, nil
+}
+
+// Capture 保存修复前的 ProxyEnable、ProxyServer、ProxyOverride 和 AutoConfigURL
+func (r *ProxyRepairer) Capture(ctx context.Context) (Rollback, error) {
+	config, err := backup.NewRegistryProxyCollector(r.store).CollectProxy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) error {
+		return backup.NewRegistryProxyApplier(r.store).ApplyProxy(ctx, config)
+	}, nil
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"sync"
 
+	"network-rescue-toolkit/pkg/audit"
+	"network-rescue-toolkit/pkg/backup"
+	"network-rescue-toolkit/pkg/executor"
+	"network-rescue-toolkit/pkg/registry"
 	"network-rescue-toolkit/pkg/types"
 )
 
@@ -15,37 +19,115 @@ type Repairer interface {
 	Name() string
 	// RequiresAdmin 是否需要管理员权限
 	RequiresAdmin() bool
+	// ModifiesConfig 是否会修改系统网络配置（修改前引擎会自动创建快照）
+	ModifiesConfig() bool
 	// Repair 执行修复并返回结果
 	Repair(ctx context.Context) types.RepairResult
 }
 
+// OnDemandRepairer 只在对应诊断项报告问题时单独执行的修复器（可选接口），综合修复会跳过
+type OnDemandRepairer interface {
+	OnDemand() bool
+}
+
+// isOnDemand 判断修复器是否只能单独执行
+func isOnDemand(r Repairer) bool {
+	onDemand, ok := r.(OnDemandRepairer)
+	return ok && onDemand.OnDemand()
+}
+
+// TargetedRepairer 声明能解决哪些诊断项的修复器（可选接口），智能修复据此选择修复器
+type TargetedRepairer interface {
+	// Targets 返回能解决的诊断项 ID
+	Targets() []string
+}
+
+// InvasiveRepairer 声明影响程度的修复器（可选接口），智能修复按影响程度从小到大执行
+type InvasiveRepairer interface {
+	// Invasiveness 影响程度，数值越大影响越大
+	Invasiveness() int
+}
+
+// VerifiableRepairer 声明修复后用哪些诊断项验证效果的修复器（可选接口）
+type VerifiableRepairer interface {
+	// VerifiedBy 返回用于验证的诊断项 ID
+	VerifiedBy() []string
+}
+
+// defaultInvasiveness 未声明影响程度的修复器排在最后
+const defaultInvasiveness = 100
+
+// targetsOf 返回修复器声明能解决的诊断项
+func targetsOf(r Repairer) []string {
+	if targeted, ok := r.(TargetedRepairer); ok {
+		return targeted.Targets()
+	}
+	return nil
+}
+
+// invasivenessOf 返回修复器的影响程度
+func invasivenessOf(r Repairer) int {
+	if invasive, ok := r.(InvasiveRepairer); ok {
+		return invasive.Invasiveness()
+	}
+	return defaultInvasiveness
+}
+
+// verifiersOf 返回修复器声明的验证诊断项
+func verifiersOf(r Repairer) []string {
+	if verifiable, ok := r.(VerifiableRepairer); ok {
+		return verifiable.VerifiedBy()
+	}
+	return nil
+}
+
 // Engine 修复引擎
 type Engine struct {
-	repairers []Repairer
-	results   []types.RepairResult
-	mu        sync.RWMutex
+	repairers      []Repairer
+	results        []types.RepairResult
+	backupManager  *backup.Manager
+	lastBackupPath string
+	verify         CheckFunc
+	undo           []undoEntry
+	undoSeq        int
+	readOnly       string
+	mu             sync.RWMutex
 }
 
-// NewEngine 创建新的修复引擎
+// NewEngine 创建修改本机配置的修复引擎，修复前快照保存到默认备份目录
 func NewEngine() *Engine {
-	e := &Engine{
+	e := NewEngineWithRunner(executor.NewCommandExecutor(), registry.NewDefaultStore())
+	e.SetBackupManager(backup.NewManager())
+	return e
+}
+
+// NewEngineWithRunner 使用指定的命令执行器和注册表创建修复引擎
+// 不创建修复前快照，需要快照时由调用方通过 SetBackupManager 设置使用同一执行器的备份管理器
+func NewEngineWithRunner(runner executor.Runner, store registry.Store) *Engine {
+	e := newEmptyEngine()
+	// 注册所有修复器
+	e.registerDefaultRepairers(runner, store)
+	return e
+}
+
+// newEmptyEngine 创建未注册任何修复器、不创建快照的修复引擎
+func newEmptyEngine() *Engine {
+	return &Engine{
 		repairers: make([]Repairer, 0),
 		results:   make([]types.RepairResult, 0),
 	}
-	// 注册所有修复器
-	e.registerDefaultRepairers()
-	return e
 }
 
 // registerDefaultRepairers 注册默认修复器
-func (e *Engine) registerDefaultRepairers() {
-	e.RegisterRepairer(NewWinsockRepairer())
-	e.RegisterRepairer(NewTCPIPRepairer())
-	e.RegisterRepairer(NewDNSRepairer())
-	e.RegisterRepairer(NewIPRepairer())
+func (e *Engine) registerDefaultRepairers(runner executor.Runner, store registry.Store) {
+	e.RegisterRepairer(NewWinsockRepairer(runner))
+	e.RegisterRepairer(NewTCPIPRepairer(runner))
+	e.RegisterRepairer(NewDNSRepairer(runner))
+	e.RegisterRepairer(NewIPRepairer(runner))
 	e.RegisterRepairer(NewHostsRepairer())
-	e.RegisterRepairer(NewProxyRepairer())
-	e.RegisterRepairer(NewAdapterRepairer())
+	e.RegisterRepairer(NewProxyRepairer(store))
+	e.RegisterRepairer(NewAdapterRepairer(runner))
+	e.RegisterRepairer(NewIPv6Repairer(runner))
 }
 
 // RegisterRepairer 注册修复器
@@ -55,32 +137,101 @@ func (e *Engine) RegisterRepairer(r Repairer) {
 	e.repairers = append(e.repairers, r)
 }
 
+// SetBackupManager 设置修复前快照使用的备份管理器，为 nil 时不创建快照
+func (e *Engine) SetBackupManager(m *backup.Manager) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.backupManager = m
+}
+
+// SetVerifier 设置修复后验证使用的诊断函数，为 nil 时不验证
+func (e *Engine) SetVerifier(check CheckFunc) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.verify = check
+}
+
+// SetReadOnly 禁止修复器修改配置（例如回放录制文件时），reason 为修复结果中显示的原因，为空时恢复正常
+func (e *Engine) SetReadOnly(reason string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.readOnly = reason
+}
+
 // Repair 执行单个修复操作
 func (e *Engine) Repair(ctx context.Context, id string) types.RepairResult {
 	for _, repairer := range e.repairers {
 		if repairer.ID() == id {
-			return repairer.Repair(ctx)
+			backupPath := ""
+			if repairer.ModifiesConfig() {
+				path, err := e.snapshot("修复前快照: " + repairer.Name())
+				if err != nil {
+					return snapshotFailure(repairer, err)
+				}
+				backupPath = path
+			}
+
+			return e.run(ctx, repairer, backupPath)
 		}
 	}
 
-	// 未找到修复器
+	return unknownRepairer(id)
+}
+
+// unknownRepairer 返回未找到修复器时的失败结果
+func unknownRepairer(id string) types.RepairResult {
 	result := types.NewRepairResult(id, "未知修复项")
 	result.SetFailure("未找到指定的修复项")
 	return *result
 }
 
+// RepairAdapters 按选择条件重置网卡（指定网卡、全部物理网卡或包含虚拟网卡）
+func (e *Engine) RepairAdapters(ctx context.Context, selection AdapterSelection) types.RepairResult {
+	for _, repairer := range e.repairers {
+		if adapter, ok := repairer.(*AdapterRepairer); ok {
+			targeted := adapter.WithSelection(selection)
+			path, err := e.snapshot("修复前快照: " + targeted.Name())
+			if err != nil {
+				return snapshotFailure(targeted, err)
+			}
+			return e.run(ctx, targeted, path)
+		}
+	}
+
+	return unknownRepairer("adapter")
+}
+
 // RepairAll 执行所有修复操作（综合修复）
+// 在第一个会修改配置的修复器执行前创建一次快照，所有修改配置的结果共享该快照
 func (e *Engine) RepairAll(ctx context.Context) []types.RepairResult {
 	e.mu.Lock()
 	e.results = make([]types.RepairResult, 0, len(e.repairers))
 	e.mu.Unlock()
 
+	backupPath := ""
 	for _, repairer := range e.repairers {
+		if isOnDemand(repairer) {
+			continue
+		}
 		select {
 		case <-ctx.Done():
 			return e.results
 		default:
-			result := repairer.Repair(ctx)
+			var result types.RepairResult
+			if repairer.ModifiesConfig() && backupPath == "" {
+				path, err := e.snapshot("综合修复前快照")
+				if err != nil {
+					// 没有快照就不继续修改配置
+					result = snapshotFailure(repairer, err)
+					e.mu.Lock()
+					e.results = append(e.results, result)
+					e.mu.Unlock()
+					return e.results
+				}
+				backupPath = path
+			}
+
+			result = e.run(ctx, repairer, backupPath)
 			e.mu.Lock()
 			e.results = append(e.results, result)
 			e.mu.Unlock()
@@ -90,6 +241,127 @@ func (e *Engine) RepairAll(ctx context.Context) []types.RepairResult {
 	return e.results
 }
 
+// run 执行修复器，并在修复前后运行验证诊断项
+// 支持回滚的修复器先保存修复前状态，修复成功后压入撤销栈；命令执行成功但验证项仍未通过时，结果标记为修复无效
+func (e *Engine) run(ctx context.Context, r Repairer, backupPath string) types.RepairResult {
+	e.mu.RLock()
+	verify := e.verify
+	readOnly := e.readOnly
+	e.mu.RUnlock()
+
+	if readOnly != "" {
+		result := types.NewRepairResult(r.ID(), r.Name())
+		result.SetFailure(readOnly)
+		return *result
+	}
+
+	checks := verifiersOf(r)
+	verifying := verify != nil && len(checks) > 0
+
+	var before []types.DiagnosticResult
+	if verifying {
+		before = runChecks(ctx, verify, checks)
+	}
+
+	ctx = audit.WithComponent(ctx, "repair/"+r.ID())
+	rollback, err := capture(ctx, r)
+	if err != nil {
+		// 无法撤销时不继续修改配置
+		result := types.NewRepairResult(r.ID(), r.Name())
+		result.SetFailure(err.Error() + "，已取消修复")
+		return *result
+	}
+
+	result := r.Repair(ctx)
+	if r.ModifiesConfig() && backupPath != "" {
+		result.SetBackupPath(backupPath)
+	}
+	// 修复失败时没有可撤销的修改，撤销会覆盖之后的其它改动
+	if rollback != nil && result.Success {
+		result.UndoSeq = e.pushUndo(r, rollback).Seq
+	}
+	if !verifying {
+		return result
+	}
+
+	verification := &types.Verification{Before: before}
+	result.Verification = verification
+
+	switch {
+	case !result.Success:
+		verification.Status = types.VerificationSkipped
+	case result.RequireReboot:
+		// 重启前诊断结果不能反映修复效果
+		verification.Status = types.VerificationPendingReboot
+	default:
+		verification.After = runChecks(ctx, verify, checks)
+		verification.Status = types.VerificationPassed
+		for _, after := range verification.After {
+			if after.NeedsRepair() {
+				verification.Status = types.VerificationIneffective
+				result.SetIneffective(result.Message + "，但" + after.Name + "仍未通过: " + after.Message)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// runChecks 依次运行诊断项
+func runChecks(ctx context.Context, verify CheckFunc, ids []string) []types.DiagnosticResult {
+	results := make([]types.DiagnosticResult, 0, len(ids))
+	for _, id := range ids {
+		results = append(results, verify(ctx, id))
+	}
+	return results
+}
+
+// snapshot 创建修复前快照并记录为最近一次快照
+func (e *Engine) snapshot(label string) (string, error) {
+	e.mu.RLock()
+	manager := e.backupManager
+	readOnly := e.readOnly
+	e.mu.RUnlock()
+	// 只读时不会修改配置，也不需要快照
+	if manager == nil || readOnly != "" {
+		return "", nil
+	}
+
+	path, err := manager.CreateBackupWithOptions(backup.BackupOptions{
+		Label:   label,
+		Trigger: backup.TriggerPreRepair,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	e.mu.Lock()
+	e.lastBackupPath = path
+	e.mu.Unlock()
+	return path, nil
+}
+
+// snapshotFailure 生成快照失败时的修复结果
+func snapshotFailure(r Repairer, err error) types.RepairResult {
+	result := types.NewRepairResult(r.ID(), r.Name())
+	result.SetFailure("创建修复前快照失败，已取消修复: " + err.Error())
+	return *result
+}
+
+// LastBackupPath 获取最近一次修复前快照路径
+func (e *Engine) LastBackupPath() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.lastBackupPath
+}
+
+// ClearLastBackup 清除最近一次修复前快照记录（撤销完成后调用）
+func (e *Engine) ClearLastBackup() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lastBackupPath = ""
+}
+
 // GetResults 获取最近一次修复结果
 func (e *Engine) GetResults() []types.RepairResult {
 	e.mu.RLock()
@@ -0,0 +1,248 @@
+package repair
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"network-rescue-toolkit/pkg/backup"
+	"network-rescue-toolkit/pkg/executor"
+	"network-rescue-toolkit/pkg/registry"
+	"network-rescue-toolkit/pkg/types"
+)
+
+// sampleIPConfig 用于修复前快照的 ipconfig 输出
+const sampleIPConfig = `
+Windows IP 配置
+
+以太网适配器 以太网:
+
+   DHCP 已启用 . . . . . . . . . . . : 是
+   IPv4 地址 . . . . . . . . . . . . : 192.168.1.20(首选)
+   子网掩码  . . . . . . . . . . . . : 255.255.255.0
+   默认网关. . . . . . . . . . . . . : 192.168.1.1
+   DNS 服务器  . . . . . . . . . . . : 192.168.1.1
+`
+
+// newTestBackupManager 创建使用样本数据的备份管理器
+func newTestBackupManager(t *testing.T, runner executor.Runner) *backup.Manager {
+	t.Helper()
+	dir := t.TempDir()
+	hostsPath := filepath.Join(dir, "hosts")
+	os.WriteFile(hostsPath, []byte("127.0.0.1 localhost\n"), 0644)
+
+	m := backup.NewManagerWithDir(filepath.Join(dir, "backups"))
+	m.SetCollectors(backup.Collectors{
+		Adapters: backup.NewIPConfigCollector(runner),
+		Proxy:    backup.NewRegistryProxyCollector(registry.NewMemoryStore()),
+		Hosts:    backup.NewFileHostsCollector(hostsPath),
+	})
+	return m
+}
+
+// newTestEngine 创建只包含命令类修复器和代理修复器的引擎
+func newTestEngine(t *testing.T) (*Engine, *executor.FakeRunner, *registry.MemoryStore) {
+	t.Helper()
+	fake := executor.NewFakeRunner().
+		OnOutput("ipconfig /all", sampleIPConfig).
+		OnOutput("netsh winsock reset", "成功地重置 Winsock 目录。").
+		OnOutput("netsh int ip reset", "正在重置 全局, 确定。").
+		OnOutput("ipconfig /flushdns", "已成功刷新 DNS 解析缓存。").
+		OnOutput("ipconfig /release", "").
+		OnOutput("ipconfig /renew", "")
+
+	store := registry.NewMemoryStore()
+	store.SetDWORD(registry.ProxySettingsPath, "ProxyEnable", 1)
+
+	e := newEmptyEngine()
+	e.RegisterRepairer(NewWinsockRepairer(fake))
+	e.RegisterRepairer(NewTCPIPRepairer(fake))
+	e.RegisterRepairer(NewDNSRepairer(fake))
+	e.RegisterRepairer(NewIPRepairer(fake))
+	e.RegisterRepairer(NewProxyRepairer(store))
+	e.SetBackupManager(newTestBackupManager(t, fake))
+	return e, fake, store
+}
+
+func TestNewEngineWithRunnerUsesInjectedDependencies(t *testing.T) {
+	fake := executor.NewFakeRunner().OnOutput("ipconfig /flushdns", "已成功刷新 DNS 解析缓存。")
+	store := registry.NewMemoryStore()
+	store.SetDWORD(registry.ProxySettingsPath, "ProxyEnable", 1)
+	e := NewEngineWithRunner(fake, store)
+
+	result := e.Repair(context.Background(), "proxy")
+	if !result.Success || result.BackupPath != "" || e.LastBackupPath() != "" {
+		t.Errorf("未设置备份管理器时不应创建快照: %+v", result)
+	}
+	if enabled, _ := store.ReadDWORD(registry.ProxySettingsPath, "ProxyEnable"); enabled != 0 {
+		t.Error("代理修复应修改传入的注册表")
+	}
+	if result := e.Repair(context.Background(), "dns"); !result.Success || !fake.Called("ipconfig /flushdns") {
+		t.Errorf("命令类修复应使用传入的执行器: %+v", result)
+	}
+}
+
+func TestRepairAllFlow(t *testing.T) {
+	e, fake, store := newTestEngine(t)
+
+	results := e.RepairAll(context.Background())
+	if len(results) != 5 {
+		t.Fatalf("期望 5 个修复结果，实际 %d", len(results))
+	}
+	for _, r := range results {
+		if !r.Success {
+			t.Errorf("修复 %s 失败: %s", r.ID, r.Message)
+		}
+	}
+	if !results[0].RequireReboot || !results[1].RequireReboot {
+		t.Error("Winsock 和 TCP/IP 重置应要求重启")
+	}
+
+	for _, cmd := range []string{"netsh winsock reset", "netsh int ip reset", "ipconfig /flushdns", "ipconfig /release", "ipconfig /renew"} {
+		if !fake.Called(cmd) {
+			t.Errorf("未执行命令: %s", cmd)
+		}
+	}
+	if enabled, _ := store.ReadDWORD(registry.ProxySettingsPath, "ProxyEnable"); enabled != 0 {
+		t.Error("代理未被禁用")
+	}
+}
+
+func TestRepairAllSharesSnapshot(t *testing.T) {
+	e, _, _ := newTestEngine(t)
+
+	results := e.RepairAll(context.Background())
+	backupPath := results[0].BackupPath
+	if backupPath == "" {
+		t.Fatal("修改配置前应创建快照")
+	}
+	if _, err := os.Stat(backupPath); err != nil {
+		t.Fatalf("快照文件不存在: %v", err)
+	}
+
+	for _, r := range results {
+		switch r.ID {
+		case "dns":
+			if r.BackupPath != "" {
+				t.Error("刷新 DNS 缓存不修改配置，不应关联快照")
+			}
+		default:
+			if r.BackupPath != backupPath {
+				t.Errorf("%s 应关联同一个快照，实际 %q", r.ID, r.BackupPath)
+			}
+		}
+	}
+	if e.LastBackupPath() != backupPath {
+		t.Errorf("LastBackupPath = %q, want %q", e.LastBackupPath(), backupPath)
+	}
+}
+
+func TestRepairSkipsSnapshotForReadOnlyRepairer(t *testing.T) {
+	e, _, _ := newTestEngine(t)
+
+	result := e.Repair(context.Background(), "dns")
+	if !result.Success || result.BackupPath != "" || e.LastBackupPath() != "" {
+		t.Errorf("刷新 DNS 缓存不应创建快照: %+v", result)
+	}
+
+	result = e.Repair(context.Background(), "winsock")
+	if result.BackupPath == "" || e.LastBackupPath() != result.BackupPath {
+		t.Errorf("Winsock 重置前应创建快照: %+v", result)
+	}
+}
+
+func TestRepairAbortsWhenSnapshotFails(t *testing.T) {
+	e, fake, _ := newTestEngine(t)
+	failing := executor.NewFakeRunner().OnFailure("ipconfig /all", 1, "拒绝访问")
+	e.SetBackupManager(newTestBackupManager(t, failing))
+
+	result := e.Repair(context.Background(), "winsock")
+	if result.Success {
+		t.Fatal("快照失败时不应执行修复")
+	}
+	if fake.Called("netsh winsock reset") {
+		t.Error("快照失败时不应执行 netsh winsock reset")
+	}
+}
+
+func TestRepairReadOnly(t *testing.T) {
+	e, _, store := newTestEngine(t)
+	fake := executor.NewFakeRunner()
+	e.SetBackupManager(newTestBackupManager(t, fake))
+	e.SetReadOnly("回放模式不能修改配置")
+
+	for _, result := range e.RepairAll(context.Background()) {
+		if result.Success || result.Message != "回放模式不能修改配置" {
+			t.Errorf("只读时应拒绝修复: %+v", result)
+		}
+	}
+	if calls := fake.Calls(); len(calls) != 0 {
+		t.Errorf("只读时不应创建快照: %v", calls)
+	}
+	if enabled, _ := store.ReadDWORD(registry.ProxySettingsPath, "ProxyEnable"); enabled != 1 {
+		t.Error("只读时不应修改代理设置")
+	}
+	if len(e.UndoStack()) != 0 || e.LastBackupPath() != "" {
+		t.Error("只读时不应留下撤销记录或快照")
+	}
+}
+
+func TestRepairCommandFailure(t *testing.T) {
+	fake := executor.NewFakeRunner().OnFailure("netsh winsock reset", 1, "请求的操作需要提升。")
+	e := newEmptyEngine()
+	e.RegisterRepairer(NewWinsockRepairer(fake))
+
+	result := e.Repair(context.Background(), "winsock")
+	if result.Success || result.RequireReboot {
+		t.Errorf("命令失败时应报告失败: %+v", result)
+	}
+}
+
+func TestRepairUnknownID(t *testing.T) {
+	e := newEmptyEngine()
+	if result := e.Repair(context.Background(), "missing"); result.Success {
+		t.Error("未知修复项应返回失败")
+	}
+}
+
+func TestRepairAllSkipsOnDemandRepairers(t *testing.T) {
+	e, fake, _ := newTestEngine(t)
+	e.RegisterRepairer(NewIPv6Repairer(fake))
+
+	for _, result := range e.RepairAll(context.Background()) {
+		if result.ID == "ipv6" {
+			t.Errorf("综合修复不应执行按需修复器: %+v", result)
+		}
+	}
+	if fake.Called("netsh interface ipv6 set prefixpolicy ::ffff:0:0/96 100 4") {
+		t.Error("综合修复不应修改前缀策略")
+	}
+}
+
+func TestRepairVerification(t *testing.T) {
+	e, _, _ := newTestEngine(t)
+	checks := &fakeChecks{statuses: map[string][]types.DiagnosticStatus{
+		"proxy": {types.StatusWarning},
+		"dns":   {types.StatusError, types.StatusError},
+	}}
+	e.SetVerifier(checks.check)
+
+	proxy := e.Repair(context.Background(), "proxy")
+	if !proxy.Success || proxy.Verification == nil || proxy.Verification.Status != types.VerificationPassed {
+		t.Errorf("代理修复后验证应通过: %+v", proxy)
+	}
+	if v := proxy.Verification; v.Before[0].Status != types.StatusWarning || v.After[0].Status != types.StatusOK {
+		t.Errorf("应记录修复前后的诊断状态: %+v", v)
+	}
+
+	dns := e.Repair(context.Background(), "dns")
+	if dns.Success || !dns.Ineffective || dns.Verification.Status != types.VerificationIneffective {
+		t.Errorf("命令成功但 DNS 仍异常时应报告修复无效: %+v", dns)
+	}
+
+	winsock := e.Repair(context.Background(), "winsock")
+	if !winsock.Success || winsock.Verification.Status != types.VerificationPendingReboot || len(winsock.Verification.After) != 0 {
+		t.Errorf("需要重启的修复不应立即验证: %+v", winsock.Verification)
+	}
+}
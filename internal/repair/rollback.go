@@ -0,0 +1,87 @@
+package repair
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"network-rescue-toolkit/pkg/audit"
+	"network-rescue-toolkit/pkg/types"
+)
+
+// Rollback 将修复器修改过的配置还原为修复前的状态
+type Rollback func(ctx context.Context) error
+
+// RollbackRepairer 支持回滚的修复器（可选接口）
+// 引擎在修复前调用 Capture 保存修复器自己会修改的配置，撤销时调用返回的 Rollback
+type RollbackRepairer interface {
+	// Capture 保存修复前的状态
+	Capture(ctx context.Context) (Rollback, error)
+}
+
+// undoEntry 撤销栈中的一次修复
+type undoEntry struct {
+	info     types.UndoEntry
+	rollback Rollback
+}
+
+// capture 修复前保存状态，修复器不支持回滚时返回 nil
+func capture(ctx context.Context, r Repairer) (Rollback, error) {
+	rollbackRepairer, ok := r.(RollbackRepairer)
+	if !ok {
+		return nil, nil
+	}
+	rollback, err := rollbackRepairer.Capture(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("保存修复前状态失败: %w", err)
+	}
+	return rollback, nil
+}
+
+// pushUndo 将修复记录压入撤销栈
+func (e *Engine) pushUndo(r Repairer, rollback Rollback) types.UndoEntry {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.undoSeq++
+	info := types.UndoEntry{
+		Seq:        e.undoSeq,
+		RepairerID: r.ID(),
+		Name:       r.Name(),
+		Timestamp:  time.Now(),
+	}
+	e.undo = append(e.undo, undoEntry{info: info, rollback: rollback})
+	return info
+}
+
+// UndoStack 返回本次会话中可撤销的修复，最近的在前
+func (e *Engine) UndoStack() []types.UndoEntry {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	entries := make([]types.UndoEntry, 0, len(e.undo))
+	for i := len(e.undo) - 1; i >= 0; i-- {
+		entries = append(entries, e.undo[i].info)
+	}
+	return entries
+}
+
+// Undo 撤销最近一次可回滚的修复；还原失败时该记录保留在栈中，可再次尝试
+func (e *Engine) Undo(ctx context.Context) (types.UndoEntry, error) {
+	e.mu.Lock()
+	if len(e.undo) == 0 {
+		e.mu.Unlock()
+		return types.UndoEntry{}, fmt.Errorf("没有可撤销的修复")
+	}
+	entry := e.undo[len(e.undo)-1]
+	e.undo = e.undo[:len(e.undo)-1]
+	e.mu.Unlock()
+
+	if err := entry.rollback(audit.WithComponent(ctx, "repair/"+entry.info.RepairerID+"/rollback")); err != nil {
+		e.mu.Lock()
+		e.undo = append(e.undo, entry)
+		e.mu.Unlock()
+		return entry.info, fmt.Errorf("撤销%s失败: %w", entry.info.Name, err)
+	}
+	return entry.info, nil
+}
@@ -0,0 +1,114 @@
+package repair
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"network-rescue-toolkit/pkg/executor"
+	"network-rescue-toolkit/pkg/registry"
+)
+
+func TestUndoProxyRepair(t *testing.T) {
+	store := registry.NewMemoryStore()
+	store.SetDWORD(registry.ProxySettingsPath, "ProxyEnable", 1)
+	store.SetString(registry.ProxySettingsPath, "ProxyServer", "127.0.0.1:7890")
+	store.SetString(registry.ProxySettingsPath, "AutoConfigURL", "http://wpad/proxy.pac")
+
+	e := newEmptyEngine()
+	e.RegisterRepairer(NewProxyRepairer(store))
+
+	result := e.Repair(context.Background(), "proxy")
+	if !result.Success || result.UndoSeq == 0 {
+		t.Fatalf("代理修复应成功并可撤销: %+v", result)
+	}
+	if stack := e.UndoStack(); len(stack) != 1 || stack[0].RepairerID != "proxy" {
+		t.Errorf("撤销栈应包含代理修复: %+v", stack)
+	}
+
+	entry, err := e.Undo(context.Background())
+	if err != nil || entry.Seq != result.UndoSeq {
+		t.Fatalf("撤销失败: %v %+v", err, entry)
+	}
+	if enabled, _ := store.ReadDWORD(registry.ProxySettingsPath, "ProxyEnable"); enabled != 1 {
+		t.Error("ProxyEnable 应恢复为 1")
+	}
+	if server, _ := store.ReadString(registry.ProxySettingsPath, "ProxyServer"); server != "127.0.0.1:7890" {
+		t.Errorf("ProxyServer 应保持原值: %q", server)
+	}
+	if url, _ := store.ReadString(registry.ProxySettingsPath, "AutoConfigURL"); url != "http://wpad/proxy.pac" {
+		t.Errorf("AutoConfigURL 应保持原值: %q", url)
+	}
+
+	if _, err := e.Undo(context.Background()); err == nil {
+		t.Error("撤销栈为空时应返回错误")
+	}
+}
+
+func TestUndoHostsRepairInOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts")
+	original := "127.0.0.1 localhost\n1.2.3.4 www.baidu.com\n"
+	os.WriteFile(path, []byte(original), 0644)
+
+	e := newEmptyEngine()
+	e.RegisterRepairer(newHostsRepairerWithPath(path))
+
+	e.Repair(context.Background(), "hosts")
+	os.WriteFile(path, []byte("# 用户修复后又手动修改\n"), 0644)
+	e.Repair(context.Background(), "hosts")
+
+	if stack := e.UndoStack(); len(stack) != 2 || stack[0].Seq != 2 {
+		t.Fatalf("撤销栈应按最近优先排列: %+v", stack)
+	}
+
+	e.Undo(context.Background())
+	if content, _ := os.ReadFile(path); string(content) != "# 用户修复后又手动修改\n" {
+		t.Errorf("第一次撤销应还原到第二次修复前: %q", content)
+	}
+	e.Undo(context.Background())
+	if content, _ := os.ReadFile(path); string(content) != original {
+		t.Errorf("第二次撤销应还原到原始内容: %q", content)
+	}
+}
+
+func TestRepairWithoutRollbackIsNotUndoable(t *testing.T) {
+	e, _, _ := newTestEngine(t)
+	if result := e.Repair(context.Background(), "dns"); result.UndoSeq != 0 || len(e.UndoStack()) != 0 {
+		t.Errorf("不支持回滚的修复不应进入撤销栈: %+v", result)
+	}
+}
+
+func TestFailedRepairIsNotUndoable(t *testing.T) {
+	e := newEmptyEngine()
+	e.RegisterRepairer(newHostsRepairerWithPath(filepath.Join(t.TempDir(), "missing", "hosts")))
+
+	result := e.Repair(context.Background(), "hosts")
+	if result.Success || result.UndoSeq != 0 || len(e.UndoStack()) != 0 {
+		t.Errorf("修复失败时不应进入撤销栈: %+v", result)
+	}
+}
+
+func TestUndoIPv6Repair(t *testing.T) {
+	fake := executor.NewFakeRunner().OnOutput("netsh interface ipv6 show prefixpolicies", `
+优先顺序  标签  前缀
+----------  -----  --------------------------------
+        40      1  ::/0
+        35      4  ::ffff:0:0/96
+`)
+	fake.SetDefault(executor.CommandResult{})
+
+	e := newEmptyEngine()
+	e.RegisterRepairer(NewIPv6Repairer(fake))
+
+	result := e.Repair(context.Background(), "ipv6")
+	if !result.Success || result.UndoSeq == 0 {
+		t.Fatalf("IPv6 修复应成功并可撤销: %+v", result)
+	}
+	if _, err := e.Undo(context.Background()); err != nil {
+		t.Fatalf("撤销失败: %v", err)
+	}
+	if !fake.Called("netsh interface ipv6 set prefixpolicy ::ffff:0:0/96 35 4") {
+		t.Errorf("撤销应恢复原来的优先级: %v", fake.Calls())
+	}
+}
@@ -10,13 +10,13 @@ import (
 
 // TCPIPRepairer TCP/IP 修复器
 type TCPIPRepairer struct {
-	executor *executor.CommandExecutor
+	executor executor.Runner
 }
 
 // NewTCPIPRepairer 创建 TCP/IP 修复器
-func NewTCPIPRepairer() *TCPIPRepairer {
+func NewTCPIPRepairer(runner executor.Runner) *TCPIPRepairer {
 	return &TCPIPRepairer{
-		executor: executor.NewCommandExecutor(),
+		executor: runner,
 	}
 }
 
@@ -35,13 +35,33 @@ func (r *TCPIPRepairer) RequiresAdmin() bool {
 	return true
 }
 
+// ModifiesConfig 是否会修改系统网络配置
+func (r *TCPIPRepairer) ModifiesConfig() bool {
+	return true
+}
+
+// Targets 返回该修复器能解决的诊断项
+func (r *TCPIPRepairer) Targets() []string {
+	return []string{"ip", "dns", "connectivity"}
+}
+
+// Invasiveness 影响程度（重置 TCP/IP 协议栈需要重启）
+func (r *TCPIPRepairer) Invasiveness() int {
+	return 90
+}
+
+// VerifiedBy 返回修复后用于验证的诊断项
+func (r *TCPIPRepairer) VerifiedBy() []string {
+	return []string{"ip", "connectivity"}
+}
+
 // Repair 执行修复
 func (r *TCPIPRepairer) Repair(ctx context.Context) types.RepairResult {
 	result := types.NewRepairResult(r.ID(), r.Name())
 	result.Timestamp = time.Now()
 
 	// 执行 netsh int ip reset
-	cmdResult := r.executor.ExecuteNetsh(ctx, "int", "ip", "reset")
+	cmdResult := r.executor.Execute(ctx, "netsh", "int", "ip", "reset")
 
 	if cmdResult.IsSuccess() {
 		result.SetSuccess("TCP/IP 协议栈重置成功，需要重启计算机以完成修复")
@@ -52,3 +72,10 @@ func (r *TCPIPRepairer) Repair(ctx context.Context) types.RepairResult {
 
 	return *result
 }
+
+// DryRun 预演
+func (r *TCPIPRepairer) DryRun(ctx context.Context) ([]types.PlannedChange, error) {
+	return []types.PlannedChange{
+		commandChange("重置 TCP/IP 协议栈，需要重启", "netsh", "int", "ip", "reset"),
+	}, nil
+}
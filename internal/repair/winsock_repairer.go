@@ -10,13 +10,13 @@ import (
 
 // WinsockRepairer Winsock 修复器
 type WinsockRepairer struct {
-	executor *executor.CommandExecutor
+	executor executor.Runner
 }
 
 // NewWinsockRepairer 创建 Winsock 修复器
-func NewWinsockRepairer() *WinsockRepairer {
+func NewWinsockRepairer(runner executor.Runner) *WinsockRepairer {
 	return &WinsockRepairer{
-		executor: executor.NewCommandExecutor(),
+		executor: runner,
 	}
 }
 
@@ -35,13 +35,33 @@ func (r *WinsockRepairer) RequiresAdmin() bool {
 	return true
 }
 
+// ModifiesConfig 是否会修改系统网络配置
+func (r *WinsockRepairer) ModifiesConfig() bool {
+	return true
+}
+
+// Targets 返回该修复器能解决的诊断项
+func (r *WinsockRepairer) Targets() []string {
+	return []string{"dns", "connectivity"}
+}
+
+// Invasiveness 影响程度（重置 Winsock 需要重启）
+func (r *WinsockRepairer) Invasiveness() int {
+	return 80
+}
+
+// VerifiedBy 返回修复后用于验证的诊断项
+func (r *WinsockRepairer) VerifiedBy() []string {
+	return []string{"dns", "connectivity"}
+}
+
 // Repair 执行修复
 func (r *WinsockRepairer) Repair(ctx context.Context) types.RepairResult {
 	result := types.NewRepairResult(r.ID(), r.Name())
 	result.Timestamp = time.Now()
 
 	// 执行 netsh winsock reset
-	cmdResult := r.executor.ExecuteNetsh(ctx, "winsock", "reset")
+	cmdResult := r.executor.Execute(ctx, "netsh", "winsock", "reset")
 
 	if cmdResult.IsSuccess() {
 		result.SetSuccess("Winsock 重置成功，需要重启计算机以完成修复")
@@ -52,3 +72,10 @@ func (r *WinsockRepairer) Repair(ctx context.Context) types.RepairResult {
 
 	return *result
 }
+
+// DryRun 预演
+func (r *WinsockRepairer) DryRun(ctx context.Context) ([]types.PlannedChange, error) {
+	return []types.PlannedChange{
+		commandChange("重置 Winsock 目录，需要重启", "netsh", "winsock", "reset"),
+	}, nil
+}
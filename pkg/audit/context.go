@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"context"
+	"sync"
+)
+
+// ComponentUnknown 未标记调用方时使用的组件名
+const ComponentUnknown = "unknown"
+
+type componentKey struct{}
+
+// WithComponent 在上下文中标记调用方组件（如 "diagnostic/ip"、"repair/dns"）
+func WithComponent(ctx context.Context, component string) context.Context {
+	return context.WithValue(ctx, componentKey{}, component)
+}
+
+// ComponentFrom 返回上下文中标记的调用方组件
+func ComponentFrom(ctx context.Context) string {
+	if ctx != nil {
+		if component, ok := ctx.Value(componentKey{}).(string); ok && component != "" {
+			return component
+		}
+	}
+	return ComponentUnknown
+}
+
+var (
+	defaultLogger *Logger
+	defaultMu     sync.RWMutex
+)
+
+// SetDefault 设置全局审计日志记录器，传入 nil 关闭审计
+func SetDefault(l *Logger) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultLogger = l
+}
+
+// Default 返回全局审计日志记录器，未设置时返回 nil（写入会被忽略）
+func Default() *Logger {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultLogger
+}
@@ -0,0 +1,286 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 审计操作类型
+const (
+	ActionExec           = "exec"            // 执行外部命令
+	ActionRegistryWrite  = "registry-write"  // 写入注册表值
+	ActionRegistryDelete = "registry-delete" // 删除注册表值
+	ActionFileWrite      = "file-write"      // 写入文件
+	ActionError          = "error"           // 未完成的操作（启动配置无效、读取状态失败等）
+)
+
+const (
+	currentLogName  = "audit.log"
+	rotatedPrefix   = "audit-"
+	rotatedTimeFmt  = "20060102-150405.000"
+	defaultMaxSize  = 1 << 20 // 单个日志文件 1MB
+	defaultMaxFiles = 10      // 含当前文件最多保留 10 个
+)
+
+// Entry 审计日志条目
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Component  string    `json:"component"`
+	Action     string    `json:"action"`
+	Target     string    `json:"target"` // 命令名、注册表值或文件路径
+	Args       []string  `json:"args,omitempty"`
+	ExitCode   int       `json:"exitCode"`
+	DurationMs int64     `json:"durationMs"`
+	Output     string    `json:"output,omitempty"` // 已脱敏并截断的输出
+	Error      string    `json:"error,omitempty"`
+}
+
+// Query 审计日志查询条件
+type Query struct {
+	From      time.Time `json:"from"`      // 为零值时不限制开始时间
+	To        time.Time `json:"to"`        // 为零值时不限制结束时间
+	Component string    `json:"component"` // 组件名或组件前缀（如 "repair" 匹配 "repair/dns"）
+	Limit     int       `json:"limit"`     // 最多返回条数，0 表示不限制
+}
+
+// Logger 审计日志记录器，按 JSON Lines 写入并按大小轮转
+type Logger struct {
+	dir      string
+	maxSize  int64
+	maxFiles int
+	mu       sync.Mutex
+}
+
+// NewLogger 创建写入指定目录的审计日志记录器
+func NewLogger(dir string) *Logger {
+	return &Logger{
+		dir:      dir,
+		maxSize:  defaultMaxSize,
+		maxFiles: defaultMaxFiles,
+	}
+}
+
+// NewDefaultLogger 创建写入 ~/.network-rescue-toolkit/logs 的审计日志记录器
+func NewDefaultLogger() *Logger {
+	homeDir, _ := os.UserHomeDir()
+	return NewLogger(filepath.Join(homeDir, ".network-rescue-toolkit", "logs"))
+}
+
+// SetRotation 设置轮转策略：单个文件最大字节数、最多保留的文件数
+func (l *Logger) SetRotation(maxSize int64, maxFiles int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if maxSize > 0 {
+		l.maxSize = maxSize
+	}
+	if maxFiles > 0 {
+		l.maxFiles = maxFiles
+	}
+}
+
+// Dir 返回日志目录
+func (l *Logger) Dir() string {
+	return l.dir
+}
+
+// Log 写入一条审计日志，输出和参数会先脱敏；nil 记录器不做任何事
+func (l *Logger) Log(entry Entry) error {
+	if l == nil {
+		return nil
+	}
+
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	if entry.Component == "" {
+		entry.Component = ComponentUnknown
+	}
+	entry.Output = RedactOutput(entry.Output)
+	entry.Args = RedactArgs(entry.Args)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("序列化审计日志失败: %w", err)
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return fmt.Errorf("创建日志目录失败: %w", err)
+	}
+	if err := l.rotateIfNeeded(int64(len(data))); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(l.dir, currentLogName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("打开审计日志失败: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("写入审计日志失败: %w", err)
+	}
+	return nil
+}
+
+// LogWrite 记录一次注册表或文件写入，组件取自上下文
+func (l *Logger) LogWrite(ctx context.Context, action, target string, args []string, start time.Time, err error) error {
+	entry := Entry{
+		Time:       start,
+		Component:  ComponentFrom(ctx),
+		Action:     action,
+		Target:     target,
+		Args:       args,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		entry.ExitCode = -1
+		entry.Error = err.Error()
+	}
+	return l.Log(entry)
+}
+
+// LogError 记录一次未能完成的操作，target 为相关的配置项或文件，组件取自上下文
+func (l *Logger) LogError(ctx context.Context, target string, err error) error {
+	return l.Log(Entry{
+		Component: ComponentFrom(ctx),
+		Action:    ActionError,
+		Target:    target,
+		ExitCode:  -1,
+		Error:     err.Error(),
+	})
+}
+
+// rotateIfNeeded 当前文件写入后超过大小上限时轮转，并删除超出数量的旧文件
+func (l *Logger) rotateIfNeeded(incoming int64) error {
+	current := filepath.Join(l.dir, currentLogName)
+	info, err := os.Stat(current)
+	if err != nil || info.Size() == 0 || info.Size()+incoming <= l.maxSize {
+		return nil
+	}
+
+	rotated := filepath.Join(l.dir, rotatedPrefix+time.Now().Format(rotatedTimeFmt)+".log")
+	if err := os.Rename(current, rotated); err != nil {
+		return fmt.Errorf("轮转审计日志失败: %w", err)
+	}
+
+	files, err := l.rotatedFiles()
+	if err != nil {
+		return err
+	}
+	// 当前文件占一个名额
+	for len(files) > l.maxFiles-1 {
+		os.Remove(files[0])
+		files = files[1:]
+	}
+	return nil
+}
+
+// rotatedFiles 返回已轮转的日志文件，按时间从旧到新排列
+func (l *Logger) rotatedFiles() ([]string, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取日志目录失败: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, rotatedPrefix) && strings.HasSuffix(name, ".log") {
+			files = append(files, filepath.Join(l.dir, name))
+		}
+	}
+	// 文件名中的时间戳按字典序即为时间顺序
+	sort.Strings(files)
+	return files, nil
+}
+
+// Query 查询审计日志，结果按时间从新到旧排列
+func (l *Logger) Query(q Query) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	files, err := l.rotatedFiles()
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, filepath.Join(l.dir, currentLogName))
+
+	results := make([]Entry, 0)
+	for _, path := range files {
+		entries, err := readEntries(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if q.matches(entry) {
+				results = append(results, entry)
+			}
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Time.After(results[j].Time)
+	})
+	if q.Limit > 0 && len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+	return results, nil
+}
+
+// matches 判断条目是否满足查询条件
+func (q Query) matches(entry Entry) bool {
+	if !q.From.IsZero() && entry.Time.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && entry.Time.After(q.To) {
+		return false
+	}
+	if q.Component != "" && entry.Component != q.Component &&
+		!strings.HasPrefix(entry.Component, q.Component+"/") {
+		return false
+	}
+	return true
+}
+
+// readEntries 读取日志文件中的全部条目，跳过损坏的行
+func readEntries(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取审计日志失败: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// 程序异常退出可能留下半行，忽略即可
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取审计日志失败: %w", err)
+	}
+	return entries, nil
+}
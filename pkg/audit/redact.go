@@ -0,0 +1,49 @@
+package audit
+
+import (
+	"regexp"
+	"unicode/utf8"
+)
+
+// maxOutputLength 日志中保留的输出最大字节数
+const maxOutputLength = 4096
+
+// redactedMark 替换敏感内容的标记
+const redactedMark = "***"
+
+var (
+	// secretPatterns 形如 "password: xxx"、"关键内容 : xxx" 的敏感字段
+	secretPatterns = regexp.MustCompile(`(?i)((?:password|passwd|pwd|passphrase|secret|token|key content|关键内容|密码)\s*[:=]\s*)\S+`)
+	// urlCredentials URL 或代理地址中的用户名和密码，代理地址可以没有协议前缀（如 ProxyServer 的 user:pass@host:port、http=user:pass@host:port）
+	urlCredentials = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://|^|[\s=;,"'])[^/\s:@;=,"']+:[^/\s@;,"']+@`)
+	// secretArgs 参数形式的敏感字段（如 netsh wlan 的 keyMaterial=xxx）
+	secretArgs = regexp.MustCompile(`(?i)^((?:key|keymaterial|password|pwd)=).+$`)
+)
+
+// RedactOutput 脱敏命令输出并截断过长内容
+func RedactOutput(output string) string {
+	output = secretPatterns.ReplaceAllString(output, "${1}"+redactedMark)
+	output = urlCredentials.ReplaceAllString(output, "${1}"+redactedMark+"@")
+	if len(output) > maxOutputLength {
+		cut := maxOutputLength
+		// 避免截断在多字节字符中间
+		for cut > 0 && !utf8.RuneStart(output[cut]) {
+			cut--
+		}
+		output = output[:cut] + "\n...(已截断)"
+	}
+	return output
+}
+
+// RedactArgs 脱敏命令参数
+func RedactArgs(args []string) []string {
+	if len(args) == 0 {
+		return args
+	}
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		arg = secretArgs.ReplaceAllString(arg, "${1}"+redactedMark)
+		redacted[i] = urlCredentials.ReplaceAllString(arg, "${1}"+redactedMark+"@")
+	}
+	return redacted
+}
@@ -0,0 +1,210 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"network-rescue-toolkit/pkg/audit"
+	"network-rescue-toolkit/pkg/executor"
+	"network-rescue-toolkit/pkg/registry"
+	"network-rescue-toolkit/pkg/types"
+)
+
+// AdapterApplier 适配器配置应用器
+type AdapterApplier interface {
+	// ApplyAddress 将地址配置（DHCP 或静态 IP/掩码/网关）应用到指定适配器
+	ApplyAddress(ctx context.Context, config types.AdapterConfig) error
+	// ApplyDNS 将 DNS 配置应用到指定适配器
+	ApplyDNS(ctx context.Context, config types.AdapterConfig) error
+}
+
+// ProxyApplier 代理配置应用器
+type ProxyApplier interface {
+	// ApplyProxy 写入当前用户的代理配置
+	ApplyProxy(ctx context.Context, config types.ProxyConfig) error
+}
+
+// HostsApplier HOSTS 文件应用器
+type HostsApplier interface {
+	// ApplyHosts 覆盖写入 HOSTS 文件
+	ApplyHosts(ctx context.Context, content string) error
+}
+
+// Appliers 还原使用的应用器集合
+type Appliers struct {
+	Adapters AdapterApplier
+	Proxy    ProxyApplier
+	Hosts    HostsApplier
+}
+
+// NetshAdapterApplier 通过 netsh 应用适配器配置
+type NetshAdapterApplier struct {
+	executor executor.Runner
+}
+
+// NewNetshAdapterApplier 创建 netsh 适配器应用器
+func NewNetshAdapterApplier(runner executor.Runner) *NetshAdapterApplier {
+	return &NetshAdapterApplier{
+		executor: runner,
+	}
+}
+
+// ApplyAddress 将地址配置应用到指定适配器
+func (a *NetshAdapterApplier) ApplyAddress(ctx context.Context, config types.AdapterConfig) error {
+	return a.run(ctx, addressCommands(config))
+}
+
+// ApplyDNS 将 DNS 配置应用到指定适配器
+func (a *NetshAdapterApplier) ApplyDNS(ctx context.Context, config types.AdapterConfig) error {
+	return a.run(ctx, dnsCommands(config))
+}
+
+// run 依次执行 netsh 命令，任一失败立即返回
+func (a *NetshAdapterApplier) run(ctx context.Context, commands [][]string) error {
+	for _, args := range commands {
+		cmdResult := a.executor.Execute(ctx, "netsh", args...)
+		if !cmdResult.IsSuccess() {
+			return fmt.Errorf("netsh %v 执行失败: %s", args, firstNonEmpty(cmdResult.Stderr, cmdResult.Stdout))
+		}
+	}
+	return nil
+}
+
+// addressCommands 生成还原地址配置所需的 netsh 参数序列
+func addressCommands(config types.AdapterConfig) [][]string {
+	commands := make([][]string, 0)
+	name := config.Name
+
+	if config.DHCPEnabled {
+		return append(commands, []string{"interface", "ip", "set", "address", name, "dhcp"})
+	}
+
+	for i, ip := range config.IPAddresses {
+		mask := ""
+		if i < len(config.SubnetMasks) {
+			mask = config.SubnetMasks[i]
+		}
+		if i == 0 {
+			args := []string{"interface", "ip", "set", "address", name, "static", ip, mask}
+			if len(config.Gateways) > 0 {
+				args = append(args, config.Gateways[0])
+			}
+			commands = append(commands, args)
+		} else {
+			commands = append(commands, []string{"interface", "ip", "add", "address", name, ip, mask})
+		}
+	}
+
+	return commands
+}
+
+// dnsCommands 生成还原 DNS 配置所需的 netsh 参数序列
+func dnsCommands(config types.AdapterConfig) [][]string {
+	commands := make([][]string, 0)
+	name := config.Name
+
+	if config.DHCPEnabled {
+		return append(commands, []string{"interface", "ip", "set", "dns", name, "dhcp"})
+	}
+
+	if len(config.DNSServers) == 0 {
+		return append(commands, []string{"interface", "ip", "set", "dns", name, "static", "none"})
+	}
+	for i, server := range config.DNSServers {
+		if i == 0 {
+			commands = append(commands, []string{"interface", "ip", "set", "dns", name, "static", server, "primary"})
+		} else {
+			commands = append(commands, []string{"interface", "ip", "add", "dns", name, server, "index=" + strconv.Itoa(i+1)})
+		}
+	}
+
+	return commands
+}
+
+// RegistryProxyApplier 将代理配置写入注册表
+type RegistryProxyApplier struct {
+	store registry.Store
+}
+
+// NewRegistryProxyApplier 创建注册表代理应用器
+func NewRegistryProxyApplier(store registry.Store) *RegistryProxyApplier {
+	return &RegistryProxyApplier{
+		store: store,
+	}
+}
+
+// ApplyProxy 写入当前用户的代理配置
+// 空字符串表示原本没有该值，对应的注册表值会被删除
+func (a *RegistryProxyApplier) ApplyProxy(ctx context.Context, config types.ProxyConfig) error {
+	var proxyEnable uint32
+	if config.Enabled {
+		proxyEnable = 1
+	}
+	start := time.Now()
+	err := a.store.WriteDWORD(registry.ProxySettingsPath, "ProxyEnable", proxyEnable)
+	audit.Default().LogWrite(ctx, audit.ActionRegistryWrite, registry.ProxySettingsPath+`\ProxyEnable`, []string{fmt.Sprint(proxyEnable)}, start, err)
+	if err != nil {
+		return fmt.Errorf("写入 ProxyEnable 失败: %w", err)
+	}
+
+	values := []struct {
+		name  string
+		value string
+	}{
+		{"ProxyServer", config.Server},
+		{"ProxyOverride", config.BypassList},
+		{"AutoConfigURL", config.AutoConfigURL},
+	}
+	for _, v := range values {
+		start := time.Now()
+		target := registry.ProxySettingsPath + `\` + v.name
+		if v.value == "" {
+			err = ignoreNotFound(a.store.DeleteValue(registry.ProxySettingsPath, v.name))
+			audit.Default().LogWrite(ctx, audit.ActionRegistryDelete, target, nil, start, err)
+		} else {
+			err = a.store.WriteString(registry.ProxySettingsPath, v.name, v.value)
+			audit.Default().LogWrite(ctx, audit.ActionRegistryWrite, target, []string{v.value}, start, err)
+		}
+		if err != nil {
+			return fmt.Errorf("写入 %s 失败: %w", v.name, err)
+		}
+	}
+
+	return nil
+}
+
+// FileHostsApplier 覆盖写入 HOSTS 文件
+type FileHostsApplier struct {
+	path string
+}
+
+// NewFileHostsApplier 创建 HOSTS 文件应用器
+func NewFileHostsApplier(path string) *FileHostsApplier {
+	return &FileHostsApplier{
+		path: path,
+	}
+}
+
+// ApplyHosts 覆盖写入 HOSTS 文件
+func (a *FileHostsApplier) ApplyHosts(ctx context.Context, content string) error {
+	start := time.Now()
+	err := os.WriteFile(a.path, []byte(content), 0644)
+	audit.Default().LogWrite(ctx, audit.ActionFileWrite, a.path, nil, start, err)
+	if err != nil {
+		return fmt.Errorf("写入 HOSTS 文件失败: %w", err)
+	}
+	return nil
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,159 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"network-rescue-toolkit/pkg/executor"
+	"network-rescue-toolkit/pkg/ipconfig"
+	"network-rescue-toolkit/pkg/registry"
+	"network-rescue-toolkit/pkg/types"
+)
+
+// AdapterCollector 适配器配置采集器
+type AdapterCollector interface {
+	// CollectAdapters 采集所有适配器的地址配置
+	CollectAdapters(ctx context.Context) ([]types.AdapterConfig, error)
+}
+
+// ProxyCollector 代理配置采集器
+type ProxyCollector interface {
+	// CollectProxy 采集当前用户的代理配置
+	CollectProxy(ctx context.Context) (types.ProxyConfig, error)
+}
+
+// HostsCollector HOSTS 文件采集器
+type HostsCollector interface {
+	// CollectHosts 读取 HOSTS 文件原始内容
+	CollectHosts(ctx context.Context) (string, error)
+}
+
+// Collectors 备份使用的采集器集合
+type Collectors struct {
+	Adapters AdapterCollector
+	Proxy    ProxyCollector
+	Hosts    HostsCollector
+}
+
+// IPConfigCollector 通过 ipconfig /all 采集适配器配置
+type IPConfigCollector struct {
+	executor executor.Runner
+}
+
+// NewIPConfigCollector 创建 ipconfig 适配器采集器
+func NewIPConfigCollector(runner executor.Runner) *IPConfigCollector {
+	return &IPConfigCollector{
+		executor: runner,
+	}
+}
+
+// CollectAdapters 采集所有适配器的地址配置
+func (c *IPConfigCollector) CollectAdapters(ctx context.Context) ([]types.AdapterConfig, error) {
+	cmdResult := c.executor.Execute(ctx, "ipconfig", "/all")
+	if !cmdResult.IsSuccess() {
+		return nil, fmt.Errorf("执行 ipconfig 失败: %s", cmdResult.Stderr)
+	}
+	return ipconfig.Parse(cmdResult.Stdout), nil
+}
+
+// RegistryProxyCollector 从注册表采集代理配置
+type RegistryProxyCollector struct {
+	store registry.Store
+}
+
+// NewRegistryProxyCollector 创建注册表代理采集器
+func NewRegistryProxyCollector(store registry.Store) *RegistryProxyCollector {
+	return &RegistryProxyCollector{
+		store: store,
+	}
+}
+
+// CollectProxy 采集当前用户的代理配置
+// 不存在的值视为未配置，其它读取错误则直接返回
+func (c *RegistryProxyCollector) CollectProxy(ctx context.Context) (types.ProxyConfig, error) {
+	config := types.ProxyConfig{}
+
+	proxyEnable, err := c.store.ReadDWORD(registry.ProxySettingsPath, "ProxyEnable")
+	if err := ignoreNotFound(err); err != nil {
+		return config, fmt.Errorf("读取 ProxyEnable 失败: %w", err)
+	}
+	config.Enabled = proxyEnable == 1
+
+	if config.Server, err = c.readString("ProxyServer"); err != nil {
+		return config, err
+	}
+	if config.BypassList, err = c.readString("ProxyOverride"); err != nil {
+		return config, err
+	}
+	if config.AutoConfigURL, err = c.readString("AutoConfigURL"); err != nil {
+		return config, err
+	}
+
+	config.Port = parseProxyPort(config.Server)
+	return config, nil
+}
+
+// readString 读取代理设置下的字符串值
+func (c *RegistryProxyCollector) readString(name string) (string, error) {
+	value, err := c.store.ReadString(registry.ProxySettingsPath, name)
+	if err := ignoreNotFound(err); err != nil {
+		return "", fmt.Errorf("读取 %s 失败: %w", name, err)
+	}
+	return value, nil
+}
+
+// FileHostsCollector 从文件系统读取 HOSTS 文件
+type FileHostsCollector struct {
+	path string
+}
+
+// NewFileHostsCollector 创建 HOSTS 文件采集器
+func NewFileHostsCollector(path string) *FileHostsCollector {
+	return &FileHostsCollector{
+		path: path,
+	}
+}
+
+// CollectHosts 读取 HOSTS 文件原始内容
+func (c *FileHostsCollector) CollectHosts(ctx context.Context) (string, error) {
+	content, err := os.ReadFile(c.path)
+	if err != nil {
+		return "", fmt.Errorf("读取 HOSTS 文件失败: %w", err)
+	}
+	return string(content), nil
+}
+
+// hostsFilePath 返回系统 HOSTS 文件路径
+func hostsFilePath() string {
+	return filepath.Join(os.Getenv("SystemRoot"), "System32", "drivers", "etc", "hosts")
+}
+
+// ignoreNotFound 忽略注册表值不存在错误
+func ignoreNotFound(err error) error {
+	if err != nil && errors.Is(err, registry.ErrValueNotFound) {
+		return nil
+	}
+	return err
+}
+
+// parseProxyPort 从 "host:port" 形式的代理地址中提取端口
+// 按协议分别配置（如 "http=h:80;https=h:443"）时返回 0
+func parseProxyPort(server string) int {
+	if server == "" || strings.Contains(server, "=") {
+		return 0
+	}
+	idx := strings.LastIndex(server, ":")
+	if idx == -1 {
+		return 0
+	}
+	port, err := strconv.Atoi(server[idx+1:])
+	if err != nil {
+		return 0
+	}
+	return port
+}
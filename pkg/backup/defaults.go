@@ -0,0 +1,24 @@
+package backup
+
+import (
+	"network-rescue-toolkit/pkg/executor"
+	"network-rescue-toolkit/pkg/registry"
+)
+
+// defaultCollectors 返回通过指定执行器和注册表读取系统配置的采集器
+func defaultCollectors(runner executor.Runner, store registry.Store) Collectors {
+	return Collectors{
+		Adapters: NewIPConfigCollector(runner),
+		Proxy:    NewRegistryProxyCollector(store),
+		Hosts:    NewFileHostsCollector(hostsFilePath()),
+	}
+}
+
+// defaultAppliers 返回通过指定执行器和注册表修改系统配置的应用器
+func defaultAppliers(runner executor.Runner, store registry.Store) Appliers {
+	return Appliers{
+		Adapters: NewNetshAdapterApplier(runner),
+		Proxy:    NewRegistryProxyApplier(store),
+		Hosts:    NewFileHostsApplier(hostsFilePath()),
+	}
+}
@@ -0,0 +1,156 @@
+package backup
+
+import "sort"
+
+// maxLCSCells 直接用最长公共子序列表比较的最大单元格数，超过时先按唯一行对齐再分段比较
+const maxLCSCells = 4 << 20
+
+// DiffLines 行级差异，相同的行省略
+// 行号对应当前文件（删除）或目标文件（新增）。先去掉相同的开头和结尾，剩余部分较小时用最长公共子序列比较；
+// 较大时（如十万行的 HOSTS 屏蔽列表）以两边都只出现一次的行为锚点分段比较，找不到锚点的片段整体替换，内存占用与行数成线性
+func DiffLines(current, target []string) []LineChange {
+	return diffRange(make([]LineChange, 0), current, target, 0, 0)
+}
+
+// diffRange 比较两个片段并追加差异，curStart、tgtStart 为片段在各自文件中的起始下标
+func diffRange(changes []LineChange, current, target []string, curStart, tgtStart int) []LineChange {
+	for len(current) > 0 && len(target) > 0 && current[0] == target[0] {
+		current, target = current[1:], target[1:]
+		curStart++
+		tgtStart++
+	}
+	for len(current) > 0 && len(target) > 0 && current[len(current)-1] == target[len(target)-1] {
+		current, target = current[:len(current)-1], target[:len(target)-1]
+	}
+
+	if len(current) == 0 || len(target) == 0 {
+		return appendReplace(changes, current, target, curStart, tgtStart)
+	}
+	if (len(current)+1)*(len(target)+1) <= maxLCSCells {
+		return diffLCS(changes, current, target, curStart, tgtStart)
+	}
+
+	anchors := uniqueAnchors(current, target)
+	if len(anchors) == 0 {
+		return appendReplace(changes, current, target, curStart, tgtStart)
+	}
+	i, j := 0, 0
+	for _, anchor := range anchors {
+		changes = diffRange(changes, current[i:anchor.current], target[j:anchor.target], curStart+i, tgtStart+j)
+		i, j = anchor.current+1, anchor.target+1
+	}
+	return diffRange(changes, current[i:], target[j:], curStart+i, tgtStart+j)
+}
+
+// diffLCS 基于最长公共子序列表比较片段
+func diffLCS(changes []LineChange, current, target []string, curStart, tgtStart int) []LineChange {
+	n, m := len(current), len(target)
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if current[i] == target[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && current[i] == target[j]:
+			i++
+			j++
+		case j < m && (i == n || lcs[i][j+1] >= lcs[i+1][j]):
+			changes = append(changes, LineChange{Op: "+", Line: target[j], LineNum: tgtStart + j + 1})
+			j++
+		default:
+			changes = append(changes, LineChange{Op: "-", Line: current[i], LineNum: curStart + i + 1})
+			i++
+		}
+	}
+	return changes
+}
+
+// appendReplace 将片段整体替换：删除当前片段的所有行，再新增目标片段的所有行
+func appendReplace(changes []LineChange, current, target []string, curStart, tgtStart int) []LineChange {
+	for i, line := range current {
+		changes = append(changes, LineChange{Op: "-", Line: line, LineNum: curStart + i + 1})
+	}
+	for j, line := range target {
+		changes = append(changes, LineChange{Op: "+", Line: line, LineNum: tgtStart + j + 1})
+	}
+	return changes
+}
+
+// lineAnchor 两个片段中内容相同的一对行
+type lineAnchor struct {
+	current int
+	target  int
+}
+
+// uniqueAnchors 找出在两个片段中都只出现一次的行，返回其中位置同时递增的最长序列
+func uniqueAnchors(current, target []string) []lineAnchor {
+	type occurrence struct {
+		currentCount, targetCount int
+		target                    int
+	}
+	seen := make(map[string]*occurrence, len(current))
+	for _, line := range current {
+		if o := seen[line]; o != nil {
+			o.currentCount++
+		} else {
+			seen[line] = &occurrence{currentCount: 1}
+		}
+	}
+	for j, line := range target {
+		if o := seen[line]; o != nil {
+			o.targetCount++
+			o.target = j
+		}
+	}
+
+	pairs := make([]lineAnchor, 0)
+	for i, line := range current {
+		if o := seen[line]; o.currentCount == 1 && o.targetCount == 1 {
+			pairs = append(pairs, lineAnchor{current: i, target: o.target})
+		}
+	}
+	return longestIncreasing(pairs)
+}
+
+// longestIncreasing 返回 pairs（已按 current 递增）中 target 也递增的最长子序列
+func longestIncreasing(pairs []lineAnchor) []lineAnchor {
+	tails := make([]int, 0)
+	prev := make([]int, len(pairs))
+	for k, pair := range pairs {
+		pos := sort.Search(len(tails), func(x int) bool {
+			return pairs[tails[x]].target >= pair.target
+		})
+		prev[k] = -1
+		if pos > 0 {
+			prev[k] = tails[pos-1]
+		}
+		if pos == len(tails) {
+			tails = append(tails, k)
+		} else {
+			tails[pos] = k
+		}
+	}
+
+	result := make([]lineAnchor, len(tails))
+	if len(tails) == 0 {
+		return result
+	}
+	for k, idx := len(tails)-1, tails[len(tails)-1]; k >= 0; k-- {
+		result[k] = pairs[idx]
+		idx = prev[idx]
+	}
+	return result
+}
@@ -0,0 +1,69 @@
+package backup
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// applyLineChanges 将差异应用到当前内容，返回应得到的目标内容
+func applyLineChanges(current []string, changes []LineChange) []string {
+	deleted := make(map[int]bool)
+	for _, change := range changes {
+		if change.Op == "-" {
+			deleted[change.LineNum] = true
+		}
+	}
+	result := make([]string, 0, len(current))
+	for i, line := range current {
+		if !deleted[i+1] {
+			result = append(result, line)
+		}
+	}
+	for _, change := range changes {
+		if change.Op == "+" {
+			at := change.LineNum - 1
+			result = append(result[:at], append([]string{change.Line}, result[at:]...)...)
+		}
+	}
+	return result
+}
+
+// blocklist 生成 n 行 HOSTS 屏蔽列表
+func blocklist(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("0.0.0.0 ads%d.example.com", i)
+	}
+	return lines
+}
+
+func TestDiffLinesLargeHosts(t *testing.T) {
+	current := blocklist(100000)
+	target := append([]string(nil), current...)
+	target[10] = "0.0.0.0 tracker.example.com"
+	target = append(target[:50000], append([]string{"1.2.3.4 www.baidu.com"}, target[50000:]...)...)
+	target = append(target[:99990], target[99991:]...)
+
+	changes := DiffLines(current, target)
+	if len(changes) != 4 {
+		t.Fatalf("应只报告 4 行变化，实际 %d 行", len(changes))
+	}
+	if got := applyLineChanges(current, changes); strings.Join(got, "\n") != strings.Join(target, "\n") {
+		t.Error("应用差异后应得到目标内容")
+	}
+}
+
+func TestDiffLinesLargeWithoutUniqueLines(t *testing.T) {
+	current := make([]string, 0, 6000)
+	target := make([]string, 0, 6000)
+	for i := 0; i < 3000; i++ {
+		current = append(current, "127.0.0.1 localhost", "# 注释")
+		target = append(target, "# 注释", "127.0.0.1 localhost")
+	}
+
+	changes := DiffLines(current, target)
+	if got := applyLineChanges(current, changes); strings.Join(got, "\n") != strings.Join(target, "\n") {
+		t.Error("没有可对齐的行时应整体替换")
+	}
+}
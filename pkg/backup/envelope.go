@@ -0,0 +1,121 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"network-rescue-toolkit/pkg/types"
+)
+
+// CurrentSchemaVersion 当前备份格式版本
+// 版本 0 为早期直接保存 NetworkConfig 的格式，没有外层信封
+const CurrentSchemaVersion = 1
+
+// Envelope 备份文件信封
+type Envelope struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	SHA256        string          `json:"sha256"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// migration 将上一版本的载荷升级到下一版本
+type migration func(payload json.RawMessage) (json.RawMessage, error)
+
+// migrations 按源版本索引的迁移步骤
+var migrations = map[int]migration{
+	0: migrateV0ToV1,
+}
+
+// encodeEnvelope 将配置封装为当前版本的信封
+func encodeEnvelope(config types.NetworkConfig) ([]byte, error) {
+	payload, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("序列化配置失败: %w", err)
+	}
+
+	envelope := Envelope{
+		SchemaVersion: CurrentSchemaVersion,
+		CreatedAt:     time.Now(),
+		SHA256:        payloadHash(payload),
+		Payload:       payload,
+	}
+	data, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("序列化备份失败: %w", err)
+	}
+	return data, nil
+}
+
+// decodeEnvelope 解析备份文件，校验完整性并迁移到当前版本
+func decodeEnvelope(data []byte) (types.NetworkConfig, error) {
+	var config types.NetworkConfig
+
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return config, fmt.Errorf("备份文件格式无效: %w", err)
+	}
+
+	envelope := Envelope{Payload: data}
+	if _, ok := probe["schemaVersion"]; ok {
+		if err := json.Unmarshal(data, &envelope); err != nil {
+			return config, fmt.Errorf("备份文件格式无效: %w", err)
+		}
+		if envelope.SHA256 == "" || payloadHash(envelope.Payload) != envelope.SHA256 {
+			return config, fmt.Errorf("备份文件校验失败，内容可能已损坏或被修改")
+		}
+	}
+
+	if envelope.SchemaVersion > CurrentSchemaVersion {
+		return config, fmt.Errorf("备份格式版本 %d 高于当前支持的版本 %d，请升级程序", envelope.SchemaVersion, CurrentSchemaVersion)
+	}
+
+	payload := envelope.Payload
+	for version := envelope.SchemaVersion; version < CurrentSchemaVersion; version++ {
+		migrate, ok := migrations[version]
+		if !ok {
+			return config, fmt.Errorf("不支持的备份格式版本: %d", version)
+		}
+		var err error
+		payload, err = migrate(payload)
+		if err != nil {
+			return config, fmt.Errorf("迁移备份格式 v%d 失败: %w", version, err)
+		}
+	}
+
+	if err := json.Unmarshal(payload, &config); err != nil {
+		return config, fmt.Errorf("解析备份内容失败: %w", err)
+	}
+	return config, nil
+}
+
+// migrateV0ToV1 早期格式没有汇总 DNS 列表，并可能缺少空数组
+func migrateV0ToV1(payload json.RawMessage) (json.RawMessage, error) {
+	var config types.NetworkConfig
+	if err := json.Unmarshal(payload, &config); err != nil {
+		return nil, err
+	}
+
+	if config.Adapters == nil {
+		config.Adapters = []types.AdapterConfig{}
+	}
+	if len(config.DNSServers) == 0 {
+		config.DNSServers = collectDNSServers(config.Adapters)
+	}
+
+	return json.Marshal(config)
+}
+
+// payloadHash 计算载荷的 SHA-256（先压缩空白，不受缩进格式影响）
+func payloadHash(payload []byte) string {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, payload); err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(compact.Bytes())
+	return hex.EncodeToString(sum[:])
+}
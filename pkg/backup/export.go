@@ -0,0 +1,299 @@
+package backup
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"network-rescue-toolkit/pkg/audit"
+	"network-rescue-toolkit/pkg/types"
+)
+
+// archiveFormat 导出归档格式标识
+const archiveFormat = "network-rescue-toolkit/backup-archive"
+
+// archiveVersion 导出归档格式版本
+const archiveVersion = 1
+
+// minPasswordLength 导出密码最小长度
+const minPasswordLength = 8
+
+// exportIterations PBKDF2 迭代次数
+var exportIterations = 600000
+
+// maxImportIterations 导入时接受的最大 PBKDF2 迭代次数
+// 迭代次数来自未经认证的归档头，不设上限时伪造的归档可以让导入长时间占满 CPU
+const maxImportIterations = 10000000
+
+// ErrWrongPassword 密码错误或归档被篡改
+var ErrWrongPassword = errors.New("密码错误或归档文件已损坏")
+
+// AdapterMismatchError 备份中的适配器在本机不存在
+type AdapterMismatchError struct {
+	Missing   []string `json:"missing"`
+	Available []string `json:"available"`
+}
+
+// Error 实现 error 接口
+func (e *AdapterMismatchError) Error() string {
+	return fmt.Sprintf("本机没有以下适配器: %s（可用: %s），请提供适配器映射",
+		strings.Join(e.Missing, ", "), strings.Join(e.Available, ", "))
+}
+
+// encryptedArchive 加密归档文件结构
+type encryptedArchive struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// archiveBundle 归档明文内容
+type archiveBundle struct {
+	ExportedAt  time.Time        `json:"exportedAt"`
+	MachineName string           `json:"machineName"`
+	AppVersion  string           `json:"appVersion"`
+	Backups     []archivedBackup `json:"backups"`
+}
+
+// archivedBackup 归档中的单个备份（Data 为原始备份文件内容）
+type archivedBackup struct {
+	Info BackupInfo      `json:"info"`
+	Data json.RawMessage `json:"data"`
+}
+
+// ExportBackups 将多个备份打包为一个使用密码加密的归档文件
+func (m *Manager) ExportBackups(paths []string, archivePath, password string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("未选择要导出的备份")
+	}
+	if len([]rune(password)) < minPasswordLength {
+		return fmt.Errorf("密码长度至少为 %d 位", minPasswordLength)
+	}
+
+	backups, err := m.ListBackups()
+	if err != nil {
+		return err
+	}
+
+	machineName, _ := os.Hostname()
+	bundle := archiveBundle{
+		ExportedAt:  time.Now(),
+		MachineName: machineName,
+		AppVersion:  m.appVersion,
+		Backups:     make([]archivedBackup, 0, len(paths)),
+	}
+
+	for _, path := range paths {
+		// 导出前校验，避免把损坏的备份带到其它机器
+		if err := m.ValidateBackup(path); err != nil {
+			return fmt.Errorf("备份 %s 校验失败: %w", filepath.Base(path), err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("读取备份文件失败: %w", err)
+		}
+
+		info := BackupInfo{Path: filepath.Base(path)}
+		for _, b := range backups {
+			if b.Path == path {
+				info = b
+				info.Path = filepath.Base(path)
+			}
+		}
+		bundle.Backups = append(bundle.Backups, archivedBackup{Info: info, Data: data})
+	}
+
+	plaintext, err := json.Marshal(bundle)
+	if err != nil {
+		return fmt.Errorf("序列化归档失败: %w", err)
+	}
+
+	archive, err := encryptArchive(plaintext, password)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化归档失败: %w", err)
+	}
+	if err := os.WriteFile(archivePath, data, 0600); err != nil {
+		return fmt.Errorf("保存归档文件失败: %w", err)
+	}
+	return nil
+}
+
+// ImportBackups 从加密归档导入备份，返回导入后的备份路径
+// 备份中的适配器名称必须在本机存在；否则需要通过 adapterMapping（归档中的名称 → 本机名称）显式指定
+func (m *Manager) ImportBackups(archivePath, password string, adapterMapping map[string]string) ([]string, error) {
+	data, err := os.ReadFile(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取归档文件失败: %w", err)
+	}
+
+	var archive encryptedArchive
+	if err := json.Unmarshal(data, &archive); err != nil || archive.Format != archiveFormat {
+		return nil, fmt.Errorf("不是有效的备份归档文件")
+	}
+	if archive.Version > archiveVersion {
+		return nil, fmt.Errorf("归档版本 %d 高于当前支持的版本 %d，请升级程序", archive.Version, archiveVersion)
+	}
+
+	plaintext, err := decryptArchive(archive, password)
+	if err != nil {
+		return nil, err
+	}
+
+	var bundle archiveBundle
+	if err := json.Unmarshal(plaintext, &bundle); err != nil {
+		return nil, fmt.Errorf("解析归档内容失败: %w", err)
+	}
+
+	// 先全部校验和映射，全部通过后再写入，避免只导入一部分
+	configs := make([]types.NetworkConfig, 0, len(bundle.Backups))
+	for _, b := range bundle.Backups {
+		config, err := decodeEnvelope(b.Data)
+		if err != nil {
+			return nil, fmt.Errorf("备份 %s 校验失败: %w", b.Info.Path, err)
+		}
+		configs = append(configs, config)
+	}
+
+	live, err := m.collectors.Adapters.CollectAdapters(audit.WithComponent(context.Background(), "backup/import"))
+	if err != nil {
+		return nil, fmt.Errorf("采集本机适配器失败: %w", err)
+	}
+	for i := range configs {
+		if err := mapAdapters(&configs[i], live, adapterMapping); err != nil {
+			return nil, err
+		}
+		if err := ValidateConfig(configs[i]); err != nil {
+			return nil, fmt.Errorf("备份 %s 校验失败: %w", bundle.Backups[i].Info.Path, err)
+		}
+	}
+
+	paths := make([]string, 0, len(configs))
+	for i, config := range configs {
+		info := bundle.Backups[i].Info
+		label := fmt.Sprintf("导入自 %s", bundle.MachineName)
+		if info.Label != "" {
+			label = info.Label + " (" + label + ")"
+		}
+
+		path, err := m.saveConfig(config, BackupOptions{Label: label, Trigger: TriggerImport})
+		if err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// mapAdapters 按映射重命名适配器，并确认所有需要还原的适配器在本机存在
+func mapAdapters(config *types.NetworkConfig, live []types.AdapterConfig, mapping map[string]string) error {
+	available := make(map[string]bool)
+	names := make([]string, 0, len(live))
+	for _, adapter := range live {
+		available[adapter.Name] = true
+		names = append(names, adapter.Name)
+	}
+
+	missing := make([]string, 0)
+	for i := range config.Adapters {
+		adapter := &config.Adapters[i]
+		if target, ok := mapping[adapter.Name]; ok {
+			adapter.Name = target
+		}
+		// 没有地址的静态适配器不会被还原，无需匹配
+		if !adapter.DHCPEnabled && len(adapter.IPAddresses) == 0 {
+			continue
+		}
+		if !available[adapter.Name] {
+			missing = append(missing, adapter.Name)
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(names)
+		return &AdapterMismatchError{Missing: missing, Available: names}
+	}
+	return nil
+}
+
+// encryptArchive 使用 PBKDF2-SHA256 派生密钥，AES-256-GCM 加密
+func encryptArchive(plaintext []byte, password string) (encryptedArchive, error) {
+	archive := encryptedArchive{
+		Format:     archiveFormat,
+		Version:    archiveVersion,
+		KDF:        "pbkdf2-sha256",
+		Iterations: exportIterations,
+		Salt:       make([]byte, 16),
+	}
+	if _, err := rand.Read(archive.Salt); err != nil {
+		return archive, fmt.Errorf("生成随机数失败: %w", err)
+	}
+
+	gcm, err := archiveCipher(password, archive.Salt, archive.Iterations)
+	if err != nil {
+		return archive, err
+	}
+
+	archive.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(archive.Nonce); err != nil {
+		return archive, fmt.Errorf("生成随机数失败: %w", err)
+	}
+	archive.Ciphertext = gcm.Seal(nil, archive.Nonce, plaintext, []byte(archiveFormat))
+	return archive, nil
+}
+
+// decryptArchive 解密归档，GCM 认证失败说明密码错误或内容被篡改
+func decryptArchive(archive encryptedArchive, password string) ([]byte, error) {
+	if archive.KDF != "pbkdf2-sha256" || archive.Iterations <= 0 {
+		return nil, fmt.Errorf("不支持的密钥派生方式: %s", archive.KDF)
+	}
+	if archive.Iterations > maxImportIterations {
+		return nil, fmt.Errorf("归档的密钥派生迭代次数 %d 超过上限 %d", archive.Iterations, maxImportIterations)
+	}
+
+	gcm, err := archiveCipher(password, archive.Salt, archive.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(archive.Nonce) != gcm.NonceSize() {
+		return nil, ErrWrongPassword
+	}
+
+	plaintext, err := gcm.Open(nil, archive.Nonce, archive.Ciphertext, []byte(archiveFormat))
+	if err != nil {
+		return nil, ErrWrongPassword
+	}
+	return plaintext, nil
+}
+
+// archiveCipher 根据密码创建 AES-GCM
+func archiveCipher(password string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, password, salt, iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("派生密钥失败: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("创建加密器失败: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,150 @@
+package backup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"network-rescue-toolkit/pkg/types"
+)
+
+func init() {
+	// 测试中降低迭代次数以加快速度
+	exportIterations = 1000
+}
+
+// exportFixture 在源机器上创建备份并导出归档
+func exportFixture(t *testing.T, adapterName string) string {
+	t.Helper()
+	source, _, _ := newFixtureManager(t)
+
+	path, err := source.saveConfig(types.NetworkConfig{
+		Adapters: []types.AdapterConfig{{
+			Name:        adapterName,
+			IPAddresses: []string{"192.168.10.20"},
+			SubnetMasks: []string{"255.255.255.0"},
+			Gateways:    []string{"192.168.10.1"},
+			DNSServers:  []string{"223.5.5.5"},
+		}},
+		HostsContent: "127.0.0.1 localhost\n",
+	}, BackupOptions{Label: "正常配置"})
+	if err != nil {
+		t.Fatalf("保存备份失败: %v", err)
+	}
+
+	archivePath := filepath.Join(t.TempDir(), "export.nrtbak")
+	if err := source.ExportBackups([]string{path}, archivePath, "correct horse"); err != nil {
+		t.Fatalf("ExportBackups 失败: %v", err)
+	}
+	return archivePath
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	archivePath := exportFixture(t, "以太网")
+
+	target, _, _ := newFixtureManager(t)
+	paths, err := target.ImportBackups(archivePath, "correct horse", nil)
+	if err != nil {
+		t.Fatalf("ImportBackups 失败: %v", err)
+	}
+	if len(paths) != 1 {
+		t.Fatalf("期望导入 1 个备份，实际 %d", len(paths))
+	}
+
+	config, err := target.loadConfig(paths[0])
+	if err != nil {
+		t.Fatalf("导入的备份无效: %v", err)
+	}
+	if config.Adapters[0].Name != "以太网" || config.Adapters[0].IPAddresses[0] != "192.168.10.20" {
+		t.Errorf("导入内容不符: %+v", config)
+	}
+
+	backups, _ := target.ListBackups()
+	if backups[0].Trigger != TriggerImport || backups[0].Label == "" {
+		t.Errorf("导入备份的元数据不符: %+v", backups[0])
+	}
+}
+
+func TestImportWrongPassword(t *testing.T) {
+	archivePath := exportFixture(t, "以太网")
+
+	target, _, _ := newFixtureManager(t)
+	if _, err := target.ImportBackups(archivePath, "wrong password", nil); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("期望密码错误，实际: %v", err)
+	}
+}
+
+func TestImportDetectsTampering(t *testing.T) {
+	archivePath := exportFixture(t, "以太网")
+
+	data, _ := os.ReadFile(archivePath)
+	// 修改密文中间的一个字符
+	idx := len(data) / 2
+	if data[idx] == 'A' {
+		data[idx] = 'B'
+	} else {
+		data[idx] = 'A'
+	}
+	os.WriteFile(archivePath, data, 0600)
+
+	target, _, _ := newFixtureManager(t)
+	if _, err := target.ImportBackups(archivePath, "correct horse", nil); err == nil {
+		t.Error("被篡改的归档不应导入成功")
+	}
+}
+
+func TestImportRejectsExcessiveIterations(t *testing.T) {
+	archive, err := encryptArchive([]byte("{}"), "correct horse")
+	if err != nil {
+		t.Fatalf("加密失败: %v", err)
+	}
+	archive.Iterations = maxImportIterations + 1
+
+	start := time.Now()
+	if _, err := decryptArchive(archive, "correct horse"); err == nil || errors.Is(err, ErrWrongPassword) {
+		t.Errorf("迭代次数超过上限时应拒绝导入: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("应在派生密钥前拒绝，实际耗时 %v", elapsed)
+	}
+}
+
+func TestImportRequiresAdapterMapping(t *testing.T) {
+	archivePath := exportFixture(t, "Ethernet 2")
+
+	target, _, _ := newFixtureManager(t)
+	_, err := target.ImportBackups(archivePath, "correct horse", nil)
+
+	var mismatch *AdapterMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("期望适配器不匹配错误，实际: %v", err)
+	}
+	if !reflect.DeepEqual(mismatch.Missing, []string{"Ethernet 2"}) ||
+		!reflect.DeepEqual(mismatch.Available, []string{"WLAN", "以太网"}) {
+		t.Errorf("不匹配信息不符: %+v", mismatch)
+	}
+	if backups, _ := target.ListBackups(); len(backups) != 0 {
+		t.Errorf("不匹配时不应导入任何备份")
+	}
+
+	paths, err := target.ImportBackups(archivePath, "correct horse", map[string]string{"Ethernet 2": "以太网"})
+	if err != nil {
+		t.Fatalf("提供映射后应导入成功: %v", err)
+	}
+	config, _ := target.loadConfig(paths[0])
+	if config.Adapters[0].Name != "以太网" {
+		t.Errorf("映射未生效: %+v", config.Adapters)
+	}
+}
+
+func TestExportRejectsShortPassword(t *testing.T) {
+	m, _, _ := newFixtureManager(t)
+	path, _ := m.saveConfig(types.NetworkConfig{}, BackupOptions{})
+
+	if err := m.ExportBackups([]string{path}, filepath.Join(t.TempDir(), "x"), "short"); err == nil {
+		t.Error("过短的密码应被拒绝")
+	}
+}
@@ -0,0 +1,239 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Trigger 备份触发方式
+type Trigger string
+
+const (
+	TriggerManual     Trigger = "manual"
+	TriggerPreRepair  Trigger = "pre-repair"
+	TriggerScheduled  Trigger = "scheduled"
+	TriggerPreRestore Trigger = "pre-restore"
+	TriggerImport     Trigger = "import"
+)
+
+// indexFileName 备份元数据索引文件名
+const indexFileName = "index.json"
+
+// BackupOptions 创建备份的选项
+type BackupOptions struct {
+	Label   string  `json:"label"`
+	Trigger Trigger `json:"trigger"`
+}
+
+// RetentionPolicy 备份保留策略，0 表示不限制；固定的备份和最新的修复前快照始终保留
+type RetentionPolicy struct {
+	KeepLast int `json:"keepLast"`
+	KeepDays int `json:"keepDays"`
+}
+
+// DefaultRetentionPolicy 默认保留策略
+var DefaultRetentionPolicy = RetentionPolicy{
+	KeepLast: 50,
+	KeepDays: 90,
+}
+
+// backupIndex 备份元数据索引（Path 字段只保存文件名，读取时再拼接备份目录）
+type backupIndex struct {
+	Backups []BackupInfo `json:"backups"`
+}
+
+// indexPath 返回索引文件路径
+func (m *Manager) indexPath() string {
+	return filepath.Join(m.backupDir, indexFileName)
+}
+
+// loadIndex 读取索引，索引不存在时从旧版备份文件迁移
+func (m *Manager) loadIndex() (backupIndex, error) {
+	index := backupIndex{Backups: make([]BackupInfo, 0)}
+
+	data, err := os.ReadFile(m.indexPath())
+	if os.IsNotExist(err) {
+		return m.adoptLegacyBackups()
+	}
+	if err != nil {
+		return index, fmt.Errorf("读取备份索引失败: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &index); err != nil {
+		return index, fmt.Errorf("解析备份索引失败: %w", err)
+	}
+	return index, nil
+}
+
+// saveIndex 保存索引（先写临时文件再替换，避免写入中断损坏索引）
+func (m *Manager) saveIndex(index backupIndex) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化备份索引失败: %w", err)
+	}
+
+	tmpPath := m.indexPath() + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("保存备份索引失败: %w", err)
+	}
+	if err := os.Rename(tmpPath, m.indexPath()); err != nil {
+		return fmt.Errorf("保存备份索引失败: %w", err)
+	}
+	return nil
+}
+
+// adoptLegacyBackups 将没有索引的旧版备份文件纳入索引
+// 只识别本工具生成的文件名，目录中的其它文件不会被列出
+func (m *Manager) adoptLegacyBackups() (backupIndex, error) {
+	index := backupIndex{Backups: make([]BackupInfo, 0)}
+
+	entries, err := os.ReadDir(m.backupDir)
+	if err != nil {
+		return index, fmt.Errorf("读取备份目录失败: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+
+		trigger := TriggerManual
+		switch {
+		case strings.HasPrefix(name, "backup_"):
+		case strings.HasPrefix(name, "safety_"):
+			trigger = TriggerPreRestore
+		default:
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		hash, err := fileHash(filepath.Join(m.backupDir, name))
+		if err != nil {
+			continue
+		}
+
+		index.Backups = append(index.Backups, BackupInfo{
+			Path:        name,
+			Timestamp:   info.ModTime(),
+			Size:        info.Size(),
+			Trigger:     trigger,
+			ContentHash: hash,
+		})
+	}
+
+	return index, nil
+}
+
+// find 按备份路径查找索引项
+func (idx *backupIndex) find(path string) int {
+	name := filepath.Base(path)
+	for i, b := range idx.Backups {
+		if b.Path == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// applyRetention 按保留策略删除过期备份，keep 指定的文件始终保留
+// 最新的修复前快照同样保留，撤销最近一次修复时还要用它还原
+func (m *Manager) applyRetention(index *backupIndex, keep string) {
+	policy := m.retention
+	if policy.KeepLast <= 0 && policy.KeepDays <= 0 {
+		return
+	}
+
+	// 按时间从新到旧排序，固定的备份不参与计数
+	sort.SliceStable(index.Backups, func(i, j int) bool {
+		return index.Backups[i].Timestamp.After(index.Backups[j].Timestamp)
+	})
+
+	cutoff := time.Now().AddDate(0, 0, -policy.KeepDays)
+	kept := make([]BackupInfo, 0, len(index.Backups))
+	rank := 0
+	latestPreRepair := true
+	for _, b := range index.Backups {
+		undoable := b.Trigger == TriggerPreRepair && latestPreRepair
+		if b.Trigger == TriggerPreRepair {
+			latestPreRepair = false
+		}
+		if b.Pinned {
+			kept = append(kept, b)
+			continue
+		}
+
+		rank++
+		expired := (policy.KeepLast > 0 && rank > policy.KeepLast) ||
+			(policy.KeepDays > 0 && b.Timestamp.Before(cutoff))
+		if !expired || b.Path == keep || undoable {
+			kept = append(kept, b)
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(m.backupDir, b.Path)); err != nil && !os.IsNotExist(err) {
+			// 删除失败时保留索引项，下次再尝试
+			kept = append(kept, b)
+		}
+	}
+	index.Backups = kept
+}
+
+// SetRetentionPolicy 设置备份保留策略（下次创建备份时生效）
+func (m *Manager) SetRetentionPolicy(policy RetentionPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retention = policy
+}
+
+// PinBackup 固定或取消固定备份，固定的备份不会被保留策略删除
+func (m *Manager) PinBackup(path string, pinned bool) error {
+	return m.updateEntry(path, func(b *BackupInfo) {
+		b.Pinned = pinned
+	})
+}
+
+// SetBackupLabel 修改备份标签
+func (m *Manager) SetBackupLabel(path, label string) error {
+	return m.updateEntry(path, func(b *BackupInfo) {
+		b.Label = label
+	})
+}
+
+// updateEntry 修改索引项并保存
+func (m *Manager) updateEntry(path string, update func(b *BackupInfo)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index, err := m.loadIndex()
+	if err != nil {
+		return err
+	}
+
+	i := index.find(path)
+	if i == -1 {
+		return fmt.Errorf("备份不存在: %s", path)
+	}
+	update(&index.Backups[i])
+	return m.saveIndex(index)
+}
+
+// fileHash 计算文件内容的 SHA-256
+func fileHash(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,169 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"network-rescue-toolkit/pkg/types"
+)
+
+func TestListBackupsIgnoresForeignFiles(t *testing.T) {
+	m, _, _ := newFixtureManager(t)
+	m.SetAppVersion("1.2.3")
+
+	path, err := m.CreateBackupWithOptions(BackupOptions{Label: "装机后", Trigger: TriggerManual})
+	if err != nil {
+		t.Fatalf("创建备份失败: %v", err)
+	}
+	os.WriteFile(filepath.Join(m.backupDir, "notes.txt"), []byte("foreign"), 0644)
+	os.WriteFile(filepath.Join(m.backupDir, "other.json"), []byte("{}"), 0644)
+
+	backups, err := m.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups 失败: %v", err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("期望 1 个备份，实际 %d: %+v", len(backups), backups)
+	}
+
+	b := backups[0]
+	expectedHash, _ := fileHash(path)
+	if b.Path != path || b.Label != "装机后" || b.Trigger != TriggerManual ||
+		b.AppVersion != "1.2.3" || b.MachineName == "" || b.ContentHash != expectedHash {
+		t.Errorf("备份元数据不符: %+v", b)
+	}
+}
+
+func TestRetentionKeepLastSkipsPinned(t *testing.T) {
+	m, _, _ := newFixtureManager(t)
+	m.SetRetentionPolicy(RetentionPolicy{KeepLast: 2})
+
+	first, err := m.saveConfig(types.NetworkConfig{}, BackupOptions{Trigger: TriggerManual})
+	if err != nil {
+		t.Fatalf("保存备份失败: %v", err)
+	}
+	if err := m.PinBackup(first, true); err != nil {
+		t.Fatalf("固定备份失败: %v", err)
+	}
+
+	paths := make([]string, 0)
+	for i := 0; i < 4; i++ {
+		path, err := m.saveConfig(types.NetworkConfig{}, BackupOptions{Trigger: TriggerPreRepair})
+		if err != nil {
+			t.Fatalf("保存备份失败: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	backups, err := m.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups 失败: %v", err)
+	}
+	if len(backups) != 3 {
+		t.Fatalf("期望保留 2 个最新备份和 1 个固定备份，实际 %d", len(backups))
+	}
+
+	remaining := make(map[string]bool)
+	for _, b := range backups {
+		remaining[b.Path] = true
+	}
+	for _, path := range []string{first, paths[2], paths[3]} {
+		if !remaining[path] {
+			t.Errorf("备份不应被删除: %s", path)
+		}
+	}
+}
+
+func TestRetentionKeepsLatestPreRepairSnapshot(t *testing.T) {
+	m, _, _ := newFixtureManager(t)
+	m.SetRetentionPolicy(RetentionPolicy{KeepLast: 2})
+
+	older, err := m.saveConfig(types.NetworkConfig{}, BackupOptions{Trigger: TriggerPreRepair})
+	if err != nil {
+		t.Fatalf("保存备份失败: %v", err)
+	}
+	latest, err := m.saveConfig(types.NetworkConfig{}, BackupOptions{Trigger: TriggerPreRepair})
+	if err != nil {
+		t.Fatalf("保存备份失败: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := m.saveConfig(types.NetworkConfig{}, BackupOptions{Trigger: TriggerScheduled}); err != nil {
+			t.Fatalf("保存备份失败: %v", err)
+		}
+	}
+
+	if _, err := os.Stat(latest); err != nil {
+		t.Errorf("撤销修复要用的最新修复前快照不应被删除: %v", err)
+	}
+	if _, err := os.Stat(older); !os.IsNotExist(err) {
+		t.Error("更早的修复前快照应按保留策略删除")
+	}
+	if backups, _ := m.ListBackups(); len(backups) != 3 {
+		t.Errorf("期望保留 2 个最新备份和最新的修复前快照，实际 %d", len(backups))
+	}
+}
+
+func TestRetentionKeepDays(t *testing.T) {
+	m, _, _ := newFixtureManager(t)
+	m.SetRetentionPolicy(RetentionPolicy{KeepDays: 7})
+
+	old, err := m.saveConfig(types.NetworkConfig{}, BackupOptions{})
+	if err != nil {
+		t.Fatalf("保存备份失败: %v", err)
+	}
+
+	// 将第一个备份的时间改为 10 天前
+	index, _ := m.loadIndex()
+	index.Backups[0].Timestamp = time.Now().AddDate(0, 0, -10)
+	if err := m.saveIndex(index); err != nil {
+		t.Fatalf("保存索引失败: %v", err)
+	}
+
+	if _, err := m.saveConfig(types.NetworkConfig{}, BackupOptions{}); err != nil {
+		t.Fatalf("保存备份失败: %v", err)
+	}
+
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Error("超过保留天数的备份应被删除")
+	}
+}
+
+func TestDeleteBackupUpdatesIndex(t *testing.T) {
+	m, _, _ := newFixtureManager(t)
+
+	path, err := m.saveConfig(types.NetworkConfig{}, BackupOptions{})
+	if err != nil {
+		t.Fatalf("保存备份失败: %v", err)
+	}
+	if err := m.SetBackupLabel(path, "test"); err != nil {
+		t.Fatalf("修改标签失败: %v", err)
+	}
+	if err := m.DeleteBackup(path); err != nil {
+		t.Fatalf("删除备份失败: %v", err)
+	}
+
+	index, _ := m.loadIndex()
+	if len(index.Backups) != 0 {
+		t.Errorf("索引项未删除: %+v", index.Backups)
+	}
+	if err := m.PinBackup(path, true); err == nil {
+		t.Error("固定已删除的备份应返回错误")
+	}
+}
+
+func TestAdoptLegacyBackups(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "backup_20250101_120000.json"), []byte("{}"), 0644)
+	os.WriteFile(filepath.Join(dir, "random.json"), []byte("{}"), 0644)
+
+	m := NewManagerWithDir(dir)
+	backups, err := m.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups 失败: %v", err)
+	}
+	if len(backups) != 1 || filepath.Base(backups[0].Path) != "backup_20250101_120000.json" {
+		t.Errorf("旧版备份迁移不符: %+v", backups)
+	}
+}
@@ -1,135 +1,336 @@
 package backup
 
 import (
-	"encoding/json"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"network-rescue-toolkit/pkg/audit"
+	"network-rescue-toolkit/pkg/executor"
+	"network-rescue-toolkit/pkg/registry"
 	"network-rescue-toolkit/pkg/types"
 )
 
 // BackupInfo 备份信息
 type BackupInfo struct {
-	Path      string    `json:"path"`
-	Timestamp time.Time `json:"timestamp"`
-	Size      int64     `json:"size"`
+	Path        string    `json:"path"`
+	Timestamp   time.Time `json:"timestamp"`
+	Size        int64     `json:"size"`
+	Label       string    `json:"label,omitempty"`
+	Trigger     Trigger   `json:"trigger"`
+	AppVersion  string    `json:"appVersion,omitempty"`
+	MachineName string    `json:"machineName,omitempty"`
+	ContentHash string    `json:"contentHash"`
+	Pinned      bool      `json:"pinned"`
 }
 
 // Manager 备份管理器
 type Manager struct {
-	backupDir string
+	backupDir  string
+	appVersion string
+	collectors Collectors
+	appliers   Appliers
+	retention  RetentionPolicy
+	mu         sync.Mutex
 }
 
 // NewManager 创建备份管理器
 func NewManager() *Manager {
+	return NewManagerWithRunner(executor.NewCommandExecutor(), registry.NewDefaultStore())
+}
+
+// NewManagerWithRunner 创建通过指定执行器和注册表采集、还原配置的备份管理器（使用默认备份目录）
+// 与诊断、修复引擎共用同一执行器时，快照执行的命令也会被录制
+func NewManagerWithRunner(runner executor.Runner, store registry.Store) *Manager {
 	// 默认备份目录在用户目录下
 	homeDir, _ := os.UserHomeDir()
-	backupDir := filepath.Join(homeDir, ".network-rescue-toolkit", "backups")
+	m := NewManagerWithDir(filepath.Join(homeDir, ".network-rescue-toolkit", "backups"))
+	m.SetCollectors(defaultCollectors(runner, store))
+	m.SetAppliers(defaultAppliers(runner, store))
+	return m
+}
+
+// NewManagerWithDir 使用指定目录创建备份管理器
+func NewManagerWithDir(backupDir string) *Manager {
 	os.MkdirAll(backupDir, 0755)
 
+	runner := executor.NewCommandExecutor()
+	store := registry.NewDefaultStore()
 	return &Manager{
-		backupDir: backupDir,
+		backupDir:  backupDir,
+		collectors: defaultCollectors(runner, store),
+		appliers:   defaultAppliers(runner, store),
+		retention:  DefaultRetentionPolicy,
 	}
 }
 
+// SetAppVersion 设置写入备份元数据的程序版本
+func (m *Manager) SetAppVersion(version string) {
+	m.appVersion = version
+}
+
+// SetCollectors 设置配置采集器
+func (m *Manager) SetCollectors(collectors Collectors) {
+	m.collectors = collectors
+}
+
+// SetAppliers 设置配置应用器
+func (m *Manager) SetAppliers(appliers Appliers) {
+	m.appliers = appliers
+}
+
+// Snapshot 采集当前网络配置快照
+func (m *Manager) Snapshot(ctx context.Context) (types.NetworkConfig, error) {
+	config := types.NetworkConfig{}
+	ctx = audit.WithComponent(ctx, "backup/snapshot")
+
+	adapters, err := m.collectors.Adapters.CollectAdapters(ctx)
+	if err != nil {
+		return config, fmt.Errorf("采集适配器配置失败: %w", err)
+	}
+
+	proxy, err := m.collectors.Proxy.CollectProxy(ctx)
+	if err != nil {
+		return config, fmt.Errorf("采集代理配置失败: %w", err)
+	}
+
+	hosts, err := m.collectors.Hosts.CollectHosts(ctx)
+	if err != nil {
+		return config, fmt.Errorf("采集 HOSTS 文件失败: %w", err)
+	}
+
+	config.Adapters = adapters
+	config.DNSServers = collectDNSServers(adapters)
+	config.ProxySettings = proxy
+	config.HostsContent = hosts
+	return config, nil
+}
+
+// collectDNSServers 汇总所有适配器的 DNS 服务器（去重并保持顺序）
+func collectDNSServers(adapters []types.AdapterConfig) []string {
+	servers := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, adapter := range adapters {
+		for _, server := range adapter.DNSServers {
+			if !seen[server] {
+				seen[server] = true
+				servers = append(servers, server)
+			}
+		}
+	}
+	return servers
+}
+
 // CreateBackup 创建配置备份
 func (m *Manager) CreateBackup() (string, error) {
-	config := types.NetworkConfig{
-		// TODO: 收集当前网络配置
-		Adapters:      []types.AdapterConfig{},
-		DNSServers:    []string{},
-		ProxySettings: types.ProxyConfig{},
-		HostsContent:  "",
+	return m.CreateBackupWithOptions(BackupOptions{Trigger: TriggerManual})
+}
+
+// CreateBackupWithOptions 使用指定标签和触发方式创建配置备份
+func (m *Manager) CreateBackupWithOptions(opts BackupOptions) (string, error) {
+	config, err := m.Snapshot(context.Background())
+	if err != nil {
+		return "", err
 	}
 
-	// 生成备份文件名
-	timestamp := time.Now().Format("20060102_150405")
-	filename := fmt.Sprintf("backup_%s.json", timestamp)
-	filepath := filepath.Join(m.backupDir, filename)
+	return m.saveConfig(config, opts)
+}
+
+// saveConfig 将配置保存为备份文件，记录元数据并执行保留策略
+func (m *Manager) saveConfig(config types.NetworkConfig, opts BackupOptions) (string, error) {
+	if opts.Trigger == "" {
+		opts.Trigger = TriggerManual
+	}
 
 	// 序列化并保存
-	data, err := json.MarshalIndent(config, "", "  ")
+	data, err := encodeEnvelope(config)
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index, err := m.loadIndex()
 	if err != nil {
-		return "", fmt.Errorf("序列化配置失败: %w", err)
+		return "", err
 	}
 
+	now := time.Now()
+	filename := m.uniqueFilename(opts.Trigger, now)
+	filepath := filepath.Join(m.backupDir, filename)
+
 	err = os.WriteFile(filepath, data, 0644)
 	if err != nil {
 		return "", fmt.Errorf("保存备份文件失败: %w", err)
 	}
 
+	sum := sha256.Sum256(data)
+	machineName, _ := os.Hostname()
+	index.Backups = append(index.Backups, BackupInfo{
+		Path:        filename,
+		Timestamp:   now,
+		Size:        int64(len(data)),
+		Label:       opts.Label,
+		Trigger:     opts.Trigger,
+		AppVersion:  m.appVersion,
+		MachineName: machineName,
+		ContentHash: hex.EncodeToString(sum[:]),
+	})
+
+	m.applyRetention(&index, filename)
+	if err := m.saveIndex(index); err != nil {
+		os.Remove(filepath)
+		return "", err
+	}
+
 	return filepath, nil
 }
 
+// uniqueFilename 生成不与现有文件冲突的备份文件名
+func (m *Manager) uniqueFilename(trigger Trigger, now time.Time) string {
+	prefix := "backup"
+	if trigger == TriggerPreRestore {
+		prefix = "safety"
+	}
 
-// RestoreBackup 还原配置备份
-func (m *Manager) RestoreBackup(path string) error {
-	// 读取备份文件
+	timestamp := now.Format("20060102_150405.000")
+	timestamp = strings.Replace(timestamp, ".", "_", 1)
+	filename := fmt.Sprintf("%s_%s.json", prefix, timestamp)
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(m.backupDir, filename)); os.IsNotExist(err) {
+			return filename
+		}
+		filename = fmt.Sprintf("%s_%s_%d.json", prefix, timestamp, i)
+	}
+}
+
+// loadConfig 读取备份文件，校验完整性、迁移格式版本并做语义校验
+func (m *Manager) loadConfig(path string) (types.NetworkConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("读取备份文件失败: %w", err)
+		return types.NetworkConfig{}, fmt.Errorf("读取备份文件失败: %w", err)
 	}
 
-	// 解析配置
-	var config types.NetworkConfig
-	err = json.Unmarshal(data, &config)
+	config, err := decodeEnvelope(data)
 	if err != nil {
-		return fmt.Errorf("解析备份文件失败: %w", err)
+		return config, err
 	}
 
-	// TODO: 应用配置
-	_ = config
+	if err := ValidateConfig(config); err != nil {
+		return config, err
+	}
+	return config, nil
+}
 
-	return nil
+// RestoreBackup 还原配置备份
+// 还原前先保存当前配置的安全快照，任一组件还原失败时自动回滚到该快照
+func (m *Manager) RestoreBackup(path string) (RestoreReport, error) {
+	return m.restore(path, nil)
 }
 
-// ListBackups 列出所有备份
-func (m *Manager) ListBackups() ([]BackupInfo, error) {
-	entries, err := os.ReadDir(m.backupDir)
+// RestoreComponents 只还原选中的组件（组件键见 RestorePlan）
+func (m *Manager) RestoreComponents(path string, components []string) (RestoreReport, error) {
+	if len(components) == 0 {
+		return RestoreReport{BackupPath: path, Components: make([]ComponentResult, 0)}, fmt.Errorf("未选择要还原的组件")
+	}
+	return m.restore(path, components)
+}
+
+// restore 还原备份，selection 为空时还原全部组件
+func (m *Manager) restore(path string, selection []string) (RestoreReport, error) {
+	ctx := audit.WithComponent(context.Background(), "backup/restore")
+	report := RestoreReport{
+		BackupPath: path,
+		Components: make([]ComponentResult, 0),
+	}
+
+	config, err := m.loadConfig(path)
 	if err != nil {
-		return nil, fmt.Errorf("读取备份目录失败: %w", err)
+		return report, err
+	}
+	// 组件键与安全快照无关，在保存安全快照前检查选择是否有效
+	if selection != nil && len(selectSteps(m.restoreSteps(config, types.NetworkConfig{}), selection)) == 0 {
+		return report, fmt.Errorf("备份中没有选中的组件: %s", strings.Join(selection, "、"))
 	}
 
-	backups := make([]BackupInfo, 0)
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
+	safety, err := m.Snapshot(ctx)
+	if err != nil {
+		return report, fmt.Errorf("创建安全快照失败: %w", err)
+	}
+	report.SafetyBackupPath, err = m.saveConfig(safety, BackupOptions{
+		Label:   "还原前安全快照",
+		Trigger: TriggerPreRestore,
+	})
+	if err != nil {
+		return report, fmt.Errorf("保存安全快照失败: %w", err)
+	}
+
+	steps := m.restoreSteps(config, safety)
+	if selection != nil {
+		steps = selectSteps(steps, selection)
+	}
+
+	err = m.applyConfig(ctx, steps, &report)
+	return report, err
+}
+
+// ListBackups 列出所有备份（按时间从新到旧）
+// 只返回索引中记录的备份，备份目录中的其它文件会被忽略
+func (m *Manager) ListBackups() ([]BackupInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index, err := m.loadIndex()
+	if err != nil {
+		return nil, err
+	}
 
-		info, err := entry.Info()
-		if err != nil {
+	backups := make([]BackupInfo, 0, len(index.Backups))
+	for _, b := range index.Backups {
+		b.Path = filepath.Join(m.backupDir, b.Path)
+		if _, err := os.Stat(b.Path); err != nil {
+			// 文件已被手动删除
 			continue
 		}
-
-		backups = append(backups, BackupInfo{
-			Path:      filepath.Join(m.backupDir, entry.Name()),
-			Timestamp: info.ModTime(),
-			Size:      info.Size(),
-		})
+		backups = append(backups, b)
 	}
 
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].Timestamp.After(backups[j].Timestamp)
+	})
 	return backups, nil
 }
 
 // DeleteBackup 删除备份
 func (m *Manager) DeleteBackup(path string) error {
-	return os.Remove(path)
-}
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-// ValidateBackup 验证备份文件完整性
-func (m *Manager) ValidateBackup(path string) error {
-	data, err := os.ReadFile(path)
+	index, err := m.loadIndex()
 	if err != nil {
-		return fmt.Errorf("读取备份文件失败: %w", err)
+		return err
 	}
 
-	var config types.NetworkConfig
-	err = json.Unmarshal(data, &config)
-	if err != nil {
-		return fmt.Errorf("备份文件格式无效: %w", err)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
 	}
 
-	return nil
+	if i := index.find(path); i != -1 {
+		index.Backups = append(index.Backups[:i], index.Backups[i+1:]...)
+	}
+	return m.saveIndex(index)
+}
+
+// ValidateBackup 验证备份文件完整性（校验和、格式版本及地址/代理等语义）
+func (m *Manager) ValidateBackup(path string) error {
+	_, err := m.loadConfig(path)
+	return err
 }
@@ -0,0 +1,319 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"network-rescue-toolkit/pkg/executor"
+	"network-rescue-toolkit/pkg/registry"
+	"network-rescue-toolkit/pkg/types"
+)
+
+// newFixtureManager 创建使用样本数据的备份管理器
+func newFixtureManager(t *testing.T) (*Manager, *registry.MemoryStore, string) {
+	t.Helper()
+
+	output, err := os.ReadFile(filepath.Join("testdata", "ipconfig_all.txt"))
+	if err != nil {
+		t.Fatalf("读取样本失败: %v", err)
+	}
+
+	dir := t.TempDir()
+	hostsPath := filepath.Join(dir, "hosts")
+	if err := os.WriteFile(hostsPath, []byte("127.0.0.1 localhost\n"), 0644); err != nil {
+		t.Fatalf("写入 HOSTS 样本失败: %v", err)
+	}
+
+	store := registry.NewMemoryStore()
+	store.SetDWORD(registry.ProxySettingsPath, "ProxyEnable", 1)
+	store.SetString(registry.ProxySettingsPath, "ProxyServer", "127.0.0.1:7890")
+	store.SetString(registry.ProxySettingsPath, "ProxyOverride", "localhost;127.*;<local>")
+	store.SetString(registry.ProxySettingsPath, "AutoConfigURL", "http://wpad/wpad.dat")
+
+	m := NewManagerWithDir(filepath.Join(dir, "backups"))
+	m.SetCollectors(Collectors{
+		Adapters: NewIPConfigCollector(executor.NewFakeRunner().OnOutput("ipconfig /all", string(output))),
+		Proxy:    NewRegistryProxyCollector(store),
+		Hosts:    NewFileHostsCollector(hostsPath),
+	})
+	return m, store, hostsPath
+}
+
+func TestSnapshotCollectsAllComponents(t *testing.T) {
+	m, _, _ := newFixtureManager(t)
+
+	config, err := m.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("Snapshot 失败: %v", err)
+	}
+
+	expectedAdapters := []types.AdapterConfig{
+		{
+			Name:        "以太网",
+			DHCPEnabled: false,
+			IPAddresses: []string{"192.168.10.20"},
+			SubnetMasks: []string{"255.255.255.0"},
+			Gateways:    []string{"192.168.10.1"},
+			DNSServers:  []string{"223.5.5.5", "114.114.114.114"},
+		},
+		{
+			Name:        "WLAN",
+			DHCPEnabled: true,
+			IPAddresses: []string{"192.168.1.105"},
+			SubnetMasks: []string{"255.255.255.0"},
+			Gateways:    []string{"192.168.1.1"},
+			DNSServers:  []string{"192.168.1.1"},
+		},
+	}
+	if !reflect.DeepEqual(config.Adapters, expectedAdapters) {
+		t.Errorf("适配器配置不符:\n got %+v\nwant %+v", config.Adapters, expectedAdapters)
+	}
+
+	expectedDNS := []string{"223.5.5.5", "114.114.114.114", "192.168.1.1"}
+	if !reflect.DeepEqual(config.DNSServers, expectedDNS) {
+		t.Errorf("DNS 汇总不符: got %v, want %v", config.DNSServers, expectedDNS)
+	}
+
+	expectedProxy := types.ProxyConfig{
+		Enabled:       true,
+		Server:        "127.0.0.1:7890",
+		Port:          7890,
+		BypassList:    "localhost;127.*;<local>",
+		AutoConfigURL: "http://wpad/wpad.dat",
+	}
+	if config.ProxySettings != expectedProxy {
+		t.Errorf("代理配置不符: got %+v, want %+v", config.ProxySettings, expectedProxy)
+	}
+
+	if config.HostsContent != "127.0.0.1 localhost\n" {
+		t.Errorf("HOSTS 内容不符: %q", config.HostsContent)
+	}
+}
+
+func TestSnapshotMissingProxyValues(t *testing.T) {
+	// 注册表中没有任何代理值时视为未配置，而不是报错
+	m, _, hostsPath := newFixtureManager(t)
+	m.collectors.Proxy = NewRegistryProxyCollector(registry.NewMemoryStore())
+	m.collectors.Hosts = NewFileHostsCollector(hostsPath)
+
+	config, err := m.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("Snapshot 失败: %v", err)
+	}
+	if config.ProxySettings != (types.ProxyConfig{}) {
+		t.Errorf("期望空代理配置，实际 %+v", config.ProxySettings)
+	}
+}
+
+func TestCreateBackupWritesSnapshot(t *testing.T) {
+	m, _, _ := newFixtureManager(t)
+
+	path, err := m.CreateBackup()
+	if err != nil {
+		t.Fatalf("CreateBackup 失败: %v", err)
+	}
+
+	config, err := m.loadConfig(path)
+	if err != nil {
+		t.Fatalf("读取备份失败: %v", err)
+	}
+	if len(config.Adapters) != 2 || !config.ProxySettings.Enabled || config.HostsContent == "" {
+		t.Errorf("备份内容不完整: %+v", config)
+	}
+}
+
+func TestCreateBackupFailsOnCollectorError(t *testing.T) {
+	// 采集失败时不应生成不完整的备份
+	m, _, _ := newFixtureManager(t)
+	m.collectors.Adapters = NewIPConfigCollector(executor.NewFakeRunner().OnFailure("ipconfig /all", 1, "ipconfig 不可用"))
+
+	if _, err := m.CreateBackup(); err == nil {
+		t.Fatal("期望采集失败时返回错误")
+	}
+
+	backups, err := m.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups 失败: %v", err)
+	}
+	if len(backups) != 0 {
+		t.Errorf("不应生成备份文件，实际 %d 个", len(backups))
+	}
+}
+
+func TestParseProxyPort(t *testing.T) {
+	cases := map[string]int{
+		"":                      0,
+		"127.0.0.1:8080":        8080,
+		"proxy.local":           0,
+		"http=h:80;https=h:443": 0,
+		"[::1]:3128":            3128,
+	}
+	for server, want := range cases {
+		if got := parseProxyPort(server); got != want {
+			t.Errorf("parseProxyPort(%q) = %d, want %d", server, got, want)
+		}
+	}
+}
+
+// recordingAdapterApplier 记录应用过的适配器配置，可指定某个适配器第一次应用时部分生效后失败
+type recordingAdapterApplier struct {
+	applied  []types.AdapterConfig
+	dns      []types.AdapterConfig
+	failName string
+}
+
+func (a *recordingAdapterApplier) ApplyAddress(ctx context.Context, config types.AdapterConfig) error {
+	a.applied = append(a.applied, config)
+	if config.Name == a.failName {
+		a.failName = ""
+		return errors.New("netsh 执行失败")
+	}
+	return nil
+}
+
+func (a *recordingAdapterApplier) ApplyDNS(ctx context.Context, config types.AdapterConfig) error {
+	a.dns = append(a.dns, config)
+	return nil
+}
+
+// newRestoreManager 创建带有样本数据和可记录应用器的管理器
+func newRestoreManager(t *testing.T) (*Manager, *registry.MemoryStore, string, *recordingAdapterApplier) {
+	t.Helper()
+	m, store, hostsPath := newFixtureManager(t)
+	adapters := &recordingAdapterApplier{}
+	m.SetAppliers(Appliers{
+		Adapters: adapters,
+		Proxy:    NewRegistryProxyApplier(store),
+		Hosts:    NewFileHostsApplier(hostsPath),
+	})
+	return m, store, hostsPath, adapters
+}
+
+func TestRestoreBackupAppliesAllComponents(t *testing.T) {
+	m, store, hostsPath, adapters := newRestoreManager(t)
+
+	target := types.NetworkConfig{
+		Adapters: []types.AdapterConfig{
+			{Name: "以太网", DHCPEnabled: true},
+		},
+		ProxySettings: types.ProxyConfig{Enabled: false, BypassList: "<local>"},
+		HostsContent:  "# clean\n",
+	}
+	path, err := m.saveConfig(target, BackupOptions{})
+	if err != nil {
+		t.Fatalf("保存备份失败: %v", err)
+	}
+
+	report, err := m.RestoreBackup(path)
+	if err != nil {
+		t.Fatalf("RestoreBackup 失败: %v", err)
+	}
+	if !report.Success || report.RolledBack {
+		t.Fatalf("期望还原成功: %+v", report)
+	}
+	if len(report.Components) != 4 {
+		t.Errorf("期望 4 个组件结果，实际 %d", len(report.Components))
+	}
+	if _, err := os.Stat(report.SafetyBackupPath); err != nil {
+		t.Errorf("安全快照未保存: %v", err)
+	}
+
+	content, _ := os.ReadFile(hostsPath)
+	if string(content) != "# clean\n" {
+		t.Errorf("HOSTS 未还原: %q", content)
+	}
+	if enabled, _ := store.ReadDWORD(registry.ProxySettingsPath, "ProxyEnable"); enabled != 0 {
+		t.Errorf("ProxyEnable 未还原: %d", enabled)
+	}
+	if _, err := store.ReadString(registry.ProxySettingsPath, "ProxyServer"); !errors.Is(err, registry.ErrValueNotFound) {
+		t.Errorf("ProxyServer 应被删除: %v", err)
+	}
+	if len(adapters.applied) != 1 || !adapters.applied[0].DHCPEnabled {
+		t.Errorf("适配器配置未应用: %+v", adapters.applied)
+	}
+}
+
+func TestRestoreBackupRollsBackOnFailure(t *testing.T) {
+	m, store, hostsPath, adapters := newRestoreManager(t)
+	adapters.failName = "WLAN"
+
+	target := types.NetworkConfig{
+		Adapters: []types.AdapterConfig{
+			{Name: "以太网", DHCPEnabled: true},
+			{Name: "WLAN", IPAddresses: []string{"10.0.0.2"}, SubnetMasks: []string{"255.0.0.0"}},
+		},
+		HostsContent: "# clean\n",
+	}
+	path, err := m.saveConfig(target, BackupOptions{})
+	if err != nil {
+		t.Fatalf("保存备份失败: %v", err)
+	}
+
+	report, err := m.RestoreBackup(path)
+	if err == nil {
+		t.Fatal("还原失败时应返回错误")
+	}
+	if report.Success || !report.RolledBack {
+		t.Fatalf("期望失败并回滚: %+v", report)
+	}
+
+	// HOSTS 和代理应回到还原前的状态
+	content, _ := os.ReadFile(hostsPath)
+	if string(content) != "127.0.0.1 localhost\n" {
+		t.Errorf("HOSTS 未回滚: %q", content)
+	}
+	if server, _ := store.ReadString(registry.ProxySettingsPath, "ProxyServer"); server != "127.0.0.1:7890" {
+		t.Errorf("ProxyServer 未回滚: %q", server)
+	}
+
+	// WLAN 部分生效后失败，应先回滚到快照中的 DHCP 配置；以太网先被设为 DHCP，随后回滚到快照中的静态配置
+	n := len(adapters.applied)
+	wlan, ethernet := adapters.applied[n-2], adapters.applied[n-1]
+	if wlan.Name != "WLAN" || !wlan.DHCPEnabled {
+		t.Errorf("失败的 WLAN 未回滚: %+v", wlan)
+	}
+	if ethernet.Name != "以太网" || ethernet.DHCPEnabled || len(ethernet.IPAddresses) == 0 {
+		t.Errorf("以太网未回滚到静态配置: %+v", ethernet)
+	}
+
+	failed := report.Components[len(report.Components)-1]
+	if failed.Success || failed.Target != "WLAN" || !strings.Contains(failed.Message, "netsh 执行失败") {
+		t.Errorf("失败组件记录不正确: %+v", failed)
+	}
+	for _, c := range report.Components {
+		if !c.RolledBack {
+			t.Errorf("组件 %s 未标记为已回滚", describeComponent(c))
+		}
+	}
+}
+
+func TestAdapterCommandsStatic(t *testing.T) {
+	config := types.AdapterConfig{
+		Name:        "Ethernet 2",
+		IPAddresses: []string{"192.168.10.20", "192.168.10.21"},
+		SubnetMasks: []string{"255.255.255.0", "255.255.255.0"},
+		Gateways:    []string{"192.168.10.1"},
+		DNSServers:  []string{"223.5.5.5", "114.114.114.114"},
+	}
+
+	expected := [][]string{
+		{"interface", "ip", "set", "address", "Ethernet 2", "static", "192.168.10.20", "255.255.255.0", "192.168.10.1"},
+		{"interface", "ip", "add", "address", "Ethernet 2", "192.168.10.21", "255.255.255.0"},
+	}
+	if commands := addressCommands(config); !reflect.DeepEqual(commands, expected) {
+		t.Errorf("地址命令不符:\n got %v\nwant %v", commands, expected)
+	}
+
+	expected = [][]string{
+		{"interface", "ip", "set", "dns", "Ethernet 2", "static", "223.5.5.5", "primary"},
+		{"interface", "ip", "add", "dns", "Ethernet 2", "114.114.114.114", "index=2"},
+	}
+	if commands := dnsCommands(config); !reflect.DeepEqual(commands, expected) {
+		t.Errorf("DNS 命令不符:\n got %v\nwant %v", commands, expected)
+	}
+}
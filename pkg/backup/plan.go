@@ -0,0 +1,198 @@
+package backup
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"network-rescue-toolkit/pkg/types"
+)
+
+// FieldChange 单个字段的变化
+type FieldChange struct {
+	Field   string `json:"field"`
+	Current string `json:"current"`
+	Target  string `json:"target"`
+}
+
+// LineChange HOSTS 文件的行变化
+type LineChange struct {
+	Op      string `json:"op"` // "+" 还原后新增，"-" 还原后删除
+	Line    string `json:"line"`
+	LineNum int    `json:"lineNum"`
+}
+
+// String 返回统一差异格式的一行，如 "+ 127.0.0.1 localhost"
+func (c LineChange) String() string {
+	return c.Op + " " + c.Line
+}
+
+// ComponentDiff 单个组件的差异
+type ComponentDiff struct {
+	Key       string        `json:"key"`
+	Component string        `json:"component"`
+	Target    string        `json:"target,omitempty"`
+	Changed   bool          `json:"changed"`
+	Available bool          `json:"available"`
+	Message   string        `json:"message,omitempty"`
+	Changes   []FieldChange `json:"changes,omitempty"`
+	Lines     []LineChange  `json:"lines,omitempty"`
+}
+
+// RestorePlan 还原预览：备份与当前配置的逐组件差异
+type RestorePlan struct {
+	BackupPath string          `json:"backupPath"`
+	Components []ComponentDiff `json:"components"`
+}
+
+// HasChanges 检查是否有任何组件需要变更
+func (p *RestorePlan) HasChanges() bool {
+	for _, c := range p.Components {
+		if c.Changed {
+			return true
+		}
+	}
+	return false
+}
+
+// PlanRestore 对比备份与当前配置，生成还原预览（不修改任何配置）
+func (m *Manager) PlanRestore(path string) (RestorePlan, error) {
+	plan := RestorePlan{
+		BackupPath: path,
+		Components: make([]ComponentDiff, 0),
+	}
+
+	target, err := m.loadConfig(path)
+	if err != nil {
+		return plan, err
+	}
+
+	current, err := m.Snapshot(context.Background())
+	if err != nil {
+		return plan, err
+	}
+
+	plan.Components = diffConfig(target, current)
+	return plan, nil
+}
+
+// diffConfig 生成各组件差异，顺序与还原步骤一致
+func diffConfig(target, current types.NetworkConfig) []ComponentDiff {
+	diffs := []ComponentDiff{
+		diffHosts(target.HostsContent, current.HostsContent),
+		diffProxy(target.ProxySettings, current.ProxySettings),
+	}
+
+	for _, adapter := range target.Adapters {
+		if !adapter.DHCPEnabled && len(adapter.IPAddresses) == 0 {
+			continue
+		}
+
+		live, ok := findAdapter(current.Adapters, adapter.Name)
+		if !ok {
+			for _, component := range []string{ComponentAdapter, ComponentDNS} {
+				diffs = append(diffs, ComponentDiff{
+					Key:       componentKey(component, adapter.Name),
+					Component: component,
+					Target:    adapter.Name,
+					Changed:   true,
+					Message:   "当前系统中未找到该适配器",
+				})
+			}
+			continue
+		}
+
+		diffs = append(diffs, diffAddress(adapter, live), diffDNS(adapter, live))
+	}
+
+	return diffs
+}
+
+// newComponentDiff 根据字段变化创建组件差异
+func newComponentDiff(component, target string, changes []FieldChange) ComponentDiff {
+	return ComponentDiff{
+		Key:       componentKey(component, target),
+		Component: component,
+		Target:    target,
+		Changed:   len(changes) > 0,
+		Available: true,
+		Changes:   changes,
+	}
+}
+
+// diffAddress 对比适配器地址配置
+func diffAddress(target, current types.AdapterConfig) ComponentDiff {
+	changes := make([]FieldChange, 0)
+	changes = appendChange(changes, "addressSource", formatSource(current.DHCPEnabled), formatSource(target.DHCPEnabled))
+
+	// DHCP 模式下地址由服务器分配，只比较获取方式
+	if !target.DHCPEnabled {
+		changes = appendChange(changes, "ipAddresses", formatList(current.IPAddresses), formatList(target.IPAddresses))
+		changes = appendChange(changes, "subnetMasks", formatList(current.SubnetMasks), formatList(target.SubnetMasks))
+		changes = appendChange(changes, "gateways", formatList(current.Gateways), formatList(target.Gateways))
+	}
+
+	return newComponentDiff(ComponentAdapter, target.Name, changes)
+}
+
+// diffDNS 对比适配器 DNS 配置
+func diffDNS(target, current types.AdapterConfig) ComponentDiff {
+	changes := make([]FieldChange, 0)
+	if target.DHCPEnabled {
+		if !current.DHCPEnabled {
+			changes = appendChange(changes, "dnsServers", formatList(current.DNSServers), formatSource(true))
+		}
+	} else {
+		changes = appendChange(changes, "dnsServers", formatList(current.DNSServers), formatList(target.DNSServers))
+	}
+	return newComponentDiff(ComponentDNS, target.Name, changes)
+}
+
+// diffProxy 对比代理配置
+func diffProxy(target, current types.ProxyConfig) ComponentDiff {
+	changes := make([]FieldChange, 0)
+	changes = appendChange(changes, "enabled", strconv.FormatBool(current.Enabled), strconv.FormatBool(target.Enabled))
+	changes = appendChange(changes, "server", current.Server, target.Server)
+	changes = appendChange(changes, "bypassList", current.BypassList, target.BypassList)
+	changes = appendChange(changes, "autoConfigUrl", current.AutoConfigURL, target.AutoConfigURL)
+	return newComponentDiff(ComponentProxy, "", changes)
+}
+
+// diffHosts 对比 HOSTS 文件内容
+func diffHosts(target, current string) ComponentDiff {
+	diff := newComponentDiff(ComponentHosts, "", nil)
+	diff.Lines = DiffLines(SplitLines(current), SplitLines(target))
+	diff.Changed = len(diff.Lines) > 0
+	return diff
+}
+
+// SplitLines 按行拆分文本，忽略换行符差异和末尾空行
+func SplitLines(content string) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.TrimRight(content, "\n")
+	if content == "" {
+		return []string{}
+	}
+	return strings.Split(content, "\n")
+}
+
+// appendChange 值不同时追加字段变化
+func appendChange(changes []FieldChange, field, current, target string) []FieldChange {
+	if current == target {
+		return changes
+	}
+	return append(changes, FieldChange{Field: field, Current: current, Target: target})
+}
+
+// formatSource 格式化地址获取方式
+func formatSource(dhcp bool) string {
+	if dhcp {
+		return "DHCP"
+	}
+	return "静态"
+}
+
+// formatList 格式化地址列表
+func formatList(values []string) string {
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,166 @@
+package backup
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"network-rescue-toolkit/pkg/registry"
+	"network-rescue-toolkit/pkg/types"
+)
+
+// findDiff 按组件键查找差异
+func findDiff(t *testing.T, plan RestorePlan, key string) ComponentDiff {
+	t.Helper()
+	for _, c := range plan.Components {
+		if c.Key == key {
+			return c
+		}
+	}
+	t.Fatalf("预览中没有组件 %s", key)
+	return ComponentDiff{}
+}
+
+func TestPlanRestoreReportsDiffs(t *testing.T) {
+	m, _, _ := newFixtureManager(t)
+
+	target := types.NetworkConfig{
+		Adapters: []types.AdapterConfig{
+			{Name: "以太网", DHCPEnabled: true},
+			{
+				Name:        "WLAN",
+				DHCPEnabled: true,
+			},
+			{Name: "VPN", DHCPEnabled: true},
+		},
+		ProxySettings: types.ProxyConfig{BypassList: "localhost;127.*;<local>"},
+		HostsContent:  "127.0.0.1 localhost\n1.2.3.4 example.com\n",
+	}
+	path, err := m.saveConfig(target, BackupOptions{})
+	if err != nil {
+		t.Fatalf("保存备份失败: %v", err)
+	}
+
+	plan, err := m.PlanRestore(path)
+	if err != nil {
+		t.Fatalf("PlanRestore 失败: %v", err)
+	}
+	if !plan.HasChanges() {
+		t.Fatal("期望存在差异")
+	}
+
+	hosts := findDiff(t, plan, "hosts")
+	expectedLines := []LineChange{{Op: "+", Line: "1.2.3.4 example.com", LineNum: 2}}
+	if !reflect.DeepEqual(hosts.Lines, expectedLines) {
+		t.Errorf("HOSTS 差异不符: %+v", hosts.Lines)
+	}
+
+	proxy := findDiff(t, plan, "proxy")
+	expectedProxy := []FieldChange{
+		{Field: "enabled", Current: "true", Target: "false"},
+		{Field: "server", Current: "127.0.0.1:7890", Target: ""},
+		{Field: "autoConfigUrl", Current: "http://wpad/wpad.dat", Target: ""},
+	}
+	if !reflect.DeepEqual(proxy.Changes, expectedProxy) {
+		t.Errorf("代理差异不符: %+v", proxy.Changes)
+	}
+
+	ethernet := findDiff(t, plan, "adapter:以太网")
+	if !ethernet.Changed || ethernet.Changes[0].Target != "DHCP" {
+		t.Errorf("以太网地址差异不符: %+v", ethernet)
+	}
+	if dns := findDiff(t, plan, "dns:以太网"); !dns.Changed {
+		t.Errorf("以太网 DNS 应有差异: %+v", dns)
+	}
+
+	// WLAN 当前已经是 DHCP，无需变更
+	if wlan := findDiff(t, plan, "adapter:WLAN"); wlan.Changed {
+		t.Errorf("WLAN 不应有差异: %+v", wlan)
+	}
+
+	if vpn := findDiff(t, plan, "adapter:VPN"); vpn.Available || !vpn.Changed {
+		t.Errorf("VPN 应标记为不可用: %+v", vpn)
+	}
+}
+
+func TestRestoreComponentsOnlyProxy(t *testing.T) {
+	m, store, hostsPath, adapters := newRestoreManager(t)
+
+	target := types.NetworkConfig{
+		Adapters:      []types.AdapterConfig{{Name: "以太网", DHCPEnabled: true}},
+		ProxySettings: types.ProxyConfig{},
+		HostsContent:  "# clean\n",
+	}
+	path, err := m.saveConfig(target, BackupOptions{})
+	if err != nil {
+		t.Fatalf("保存备份失败: %v", err)
+	}
+
+	report, err := m.RestoreComponents(path, []string{"proxy"})
+	if err != nil {
+		t.Fatalf("RestoreComponents 失败: %v", err)
+	}
+	if !report.Success || len(report.Components) != 1 || report.Components[0].Component != ComponentProxy {
+		t.Fatalf("期望只还原代理: %+v", report)
+	}
+
+	if enabled, _ := store.ReadDWORD(registry.ProxySettingsPath, "ProxyEnable"); enabled != 0 {
+		t.Errorf("代理未还原")
+	}
+	if content, _ := os.ReadFile(hostsPath); string(content) != "127.0.0.1 localhost\n" {
+		t.Errorf("HOSTS 不应被修改: %q", content)
+	}
+	if len(adapters.applied) != 0 || len(adapters.dns) != 0 {
+		t.Errorf("适配器不应被修改: %+v %+v", adapters.applied, adapters.dns)
+	}
+}
+
+func TestRestoreComponentsByKey(t *testing.T) {
+	m, _, _, adapters := newRestoreManager(t)
+
+	target := types.NetworkConfig{
+		Adapters: []types.AdapterConfig{
+			{Name: "以太网", DHCPEnabled: true},
+			{Name: "WLAN", DHCPEnabled: true},
+		},
+	}
+	path, err := m.saveConfig(target, BackupOptions{})
+	if err != nil {
+		t.Fatalf("保存备份失败: %v", err)
+	}
+
+	if _, err := m.RestoreComponents(path, nil); err == nil {
+		t.Error("未选择组件时应返回错误")
+	}
+	if report, err := m.RestoreComponents(path, []string{"dns:VPN"}); err == nil || report.Success || report.SafetyBackupPath != "" {
+		t.Errorf("选择的组件不在备份中时应返回错误且不保存安全快照: %+v", report)
+	}
+
+	report, err := m.RestoreComponents(path, []string{"dns:WLAN"})
+	if err != nil {
+		t.Fatalf("RestoreComponents 失败: %v", err)
+	}
+	if !report.Success || len(adapters.applied) != 0 || len(adapters.dns) != 1 || adapters.dns[0].Name != "WLAN" {
+		t.Errorf("期望只还原 WLAN 的 DNS: %+v %+v", report, adapters.dns)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	current := []string{"a", "b", "c"}
+	target := []string{"a", "c", "d"}
+
+	expected := []LineChange{
+		{Op: "-", Line: "b", LineNum: 2},
+		{Op: "+", Line: "d", LineNum: 3},
+	}
+	if got := DiffLines(current, target); !reflect.DeepEqual(got, expected) {
+		t.Errorf("DiffLines = %+v, want %+v", got, expected)
+	}
+}
+
+func TestLineChangeString(t *testing.T) {
+	changes := DiffLines(SplitLines("127.0.0.1 localhost\r\n1.2.3.4 example.com\r\n"), SplitLines("127.0.0.1 localhost\n"))
+	if len(changes) != 1 || changes[0].String() != "- 1.2.3.4 example.com" {
+		t.Errorf("差异格式不符: %+v", changes)
+	}
+}
@@ -0,0 +1,213 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"network-rescue-toolkit/pkg/types"
+)
+
+// 还原组件标识
+const (
+	ComponentHosts   = "hosts"
+	ComponentProxy   = "proxy"
+	ComponentAdapter = "adapter"
+	ComponentDNS     = "dns"
+)
+
+// ComponentResult 单个组件的还原结果
+type ComponentResult struct {
+	Component  string `json:"component"`
+	Target     string `json:"target,omitempty"`
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	RolledBack bool   `json:"rolledBack"`
+}
+
+// RestoreReport 还原报告
+type RestoreReport struct {
+	BackupPath       string            `json:"backupPath"`
+	SafetyBackupPath string            `json:"safetyBackupPath"`
+	Success          bool              `json:"success"`
+	RolledBack       bool              `json:"rolledBack"`
+	Message          string            `json:"message"`
+	Components       []ComponentResult `json:"components"`
+}
+
+// restoreStep 还原步骤
+type restoreStep struct {
+	component string
+	target    string
+	apply     func(ctx context.Context) error
+	rollback  func(ctx context.Context) error
+}
+
+// restoreSteps 根据目标配置和安全快照生成还原步骤
+// 顺序为 HOSTS → 代理 → 各适配器的地址和 DNS，影响面从小到大
+func (m *Manager) restoreSteps(target, safety types.NetworkConfig) []restoreStep {
+	steps := []restoreStep{
+		{
+			component: ComponentHosts,
+			apply: func(ctx context.Context) error {
+				return m.appliers.Hosts.ApplyHosts(ctx, target.HostsContent)
+			},
+			rollback: func(ctx context.Context) error {
+				return m.appliers.Hosts.ApplyHosts(ctx, safety.HostsContent)
+			},
+		},
+		{
+			component: ComponentProxy,
+			apply: func(ctx context.Context) error {
+				return m.appliers.Proxy.ApplyProxy(ctx, target.ProxySettings)
+			},
+			rollback: func(ctx context.Context) error {
+				return m.appliers.Proxy.ApplyProxy(ctx, safety.ProxySettings)
+			},
+		},
+	}
+
+	for _, adapter := range target.Adapters {
+		adapter := adapter
+		if !adapter.DHCPEnabled && len(adapter.IPAddresses) == 0 {
+			// 未连接或没有地址的静态适配器无需还原
+			continue
+		}
+		previous, hasPrevious := findAdapter(safety.Adapters, adapter.Name)
+
+		address := restoreStep{
+			component: ComponentAdapter,
+			target:    adapter.Name,
+			apply: func(ctx context.Context) error {
+				return m.appliers.Adapters.ApplyAddress(ctx, adapter)
+			},
+		}
+		dns := restoreStep{
+			component: ComponentDNS,
+			target:    adapter.Name,
+			apply: func(ctx context.Context) error {
+				return m.appliers.Adapters.ApplyDNS(ctx, adapter)
+			},
+		}
+		if hasPrevious {
+			address.rollback = func(ctx context.Context) error {
+				return m.appliers.Adapters.ApplyAddress(ctx, previous)
+			}
+			dns.rollback = func(ctx context.Context) error {
+				return m.appliers.Adapters.ApplyDNS(ctx, previous)
+			}
+		}
+		steps = append(steps, address, dns)
+	}
+
+	return steps
+}
+
+// selectSteps 按组件键筛选还原步骤
+// 选择项可以是完整键（如 "dns:以太网"），也可以是组件名（如 "adapter" 表示所有适配器）
+func selectSteps(steps []restoreStep, selection []string) []restoreStep {
+	selected := make([]restoreStep, 0, len(steps))
+	for _, step := range steps {
+		key := componentKey(step.component, step.target)
+		for _, s := range selection {
+			if s == key || s == step.component {
+				selected = append(selected, step)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// componentKey 生成组件键
+func componentKey(component, target string) string {
+	if target == "" {
+		return component
+	}
+	return component + ":" + target
+}
+
+// applyConfig 事务性地应用配置：任一步骤失败则按相反顺序回滚该步骤及之前已应用的步骤
+// 失败的步骤可能已部分生效（如多条 netsh 命令中的前几条），回滚操作是幂等的，一并回滚
+func (m *Manager) applyConfig(ctx context.Context, steps []restoreStep, report *RestoreReport) error {
+	applied := make([]int, 0, len(steps))
+
+	for i, step := range steps {
+		result := ComponentResult{
+			Component: step.component,
+			Target:    step.target,
+		}
+
+		if err := step.apply(ctx); err != nil {
+			result.Message = err.Error()
+			report.Components = append(report.Components, result)
+			report.Message = fmt.Sprintf("还原 %s 失败: %v", describeComponent(result), err)
+			m.rollback(ctx, steps, append(applied, i), report)
+			return fmt.Errorf("还原 %s 失败: %w", describeComponent(result), err)
+		}
+
+		result.Success = true
+		result.Message = "已还原"
+		report.Components = append(report.Components, result)
+		applied = append(applied, i)
+	}
+
+	report.Success = true
+	report.Message = "配置已还原"
+	return nil
+}
+
+// rollback 回滚步骤（包括最后失败的步骤）
+func (m *Manager) rollback(ctx context.Context, steps []restoreStep, applied []int, report *RestoreReport) {
+	report.RolledBack = true
+
+	for i := len(applied) - 1; i >= 0; i-- {
+		idx := applied[i]
+		step := steps[idx]
+		component := &report.Components[idx]
+
+		prefix := "已还原，但"
+		if !component.Success {
+			prefix = component.Message + "，"
+		}
+		if step.rollback == nil {
+			component.Message = prefix + "安全快照中没有该项，无法回滚"
+			report.RolledBack = false
+			continue
+		}
+		if err := step.rollback(ctx); err != nil {
+			component.Message = prefix + "回滚失败: " + err.Error()
+			report.RolledBack = false
+			continue
+		}
+		component.RolledBack = true
+		if component.Success {
+			component.Message = "已回滚"
+		} else {
+			component.Message = prefix + "已回滚"
+		}
+	}
+
+	if report.RolledBack {
+		report.Message += "，已自动回滚到还原前的配置"
+	} else {
+		report.Message += "，部分配置回滚失败，请使用安全快照手动还原"
+	}
+}
+
+// findAdapter 按名称查找适配器配置
+func findAdapter(adapters []types.AdapterConfig, name string) (types.AdapterConfig, bool) {
+	for _, adapter := range adapters {
+		if adapter.Name == name {
+			return adapter, true
+		}
+	}
+	return types.AdapterConfig{}, false
+}
+
+// describeComponent 生成组件描述
+func describeComponent(result ComponentResult) string {
+	if result.Target != "" {
+		return result.Component + " (" + result.Target + ")"
+	}
+	return result.Component
+}
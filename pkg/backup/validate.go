@@ -0,0 +1,234 @@
+package backup
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"network-rescue-toolkit/pkg/types"
+)
+
+// ValidationError 备份语义校验错误
+type ValidationError struct {
+	Issues []string `json:"issues"`
+}
+
+// Error 实现 error 接口
+func (e *ValidationError) Error() string {
+	return "备份内容无效: " + strings.Join(e.Issues, "; ")
+}
+
+// validator 收集校验问题
+type validator struct {
+	issues []string
+}
+
+// addf 记录一条问题
+func (v *validator) addf(format string, args ...any) {
+	v.issues = append(v.issues, fmt.Sprintf(format, args...))
+}
+
+// ValidateConfig 对网络配置做语义校验，避免还原后网络不可用
+func ValidateConfig(config types.NetworkConfig) error {
+	v := &validator{}
+
+	for _, adapter := range config.Adapters {
+		v.validateAdapter(adapter)
+	}
+	for _, server := range config.DNSServers {
+		if parseAddress(server) == nil {
+			v.addf("DNS 服务器地址无效: %q", server)
+		}
+	}
+	v.validateProxy(config.ProxySettings)
+
+	if len(v.issues) > 0 {
+		return &ValidationError{Issues: v.issues}
+	}
+	return nil
+}
+
+// validateAdapter 校验适配器地址配置
+func (v *validator) validateAdapter(adapter types.AdapterConfig) {
+	if strings.TrimSpace(adapter.Name) == "" {
+		v.addf("存在未命名的适配器")
+		return
+	}
+
+	subnets := make([]*net.IPNet, 0)
+	for i, address := range adapter.IPAddresses {
+		subnet, err := adapterSubnet(address, adapter.SubnetMasks, i)
+		if err != nil {
+			v.addf("适配器 %s: %v", adapter.Name, err)
+			continue
+		}
+		if subnet != nil {
+			subnets = append(subnets, subnet)
+		}
+	}
+
+	if !adapter.DHCPEnabled && len(adapter.IPAddresses) > 0 && len(adapter.SubnetMasks) < len(adapter.IPAddresses) {
+		v.addf("适配器 %s: 静态地址数量 (%d) 与子网掩码数量 (%d) 不一致", adapter.Name, len(adapter.IPAddresses), len(adapter.SubnetMasks))
+	}
+
+	for _, gateway := range adapter.Gateways {
+		ip := parseAddress(gateway)
+		if ip == nil {
+			v.addf("适配器 %s: 网关地址无效: %q", adapter.Name, gateway)
+			continue
+		}
+		// DHCP 的网关由服务器下发，只校验静态配置；IPv6 链路本地网关不属于任何前缀
+		if adapter.DHCPEnabled || ip.IsLinkLocalUnicast() {
+			continue
+		}
+		if !inAnySubnet(ip, subnets) {
+			v.addf("适配器 %s: 网关 %s 不在任何已配置的子网内", adapter.Name, gateway)
+		}
+	}
+
+	for _, server := range adapter.DNSServers {
+		if parseAddress(server) == nil {
+			v.addf("适配器 %s: DNS 服务器地址无效: %q", adapter.Name, server)
+		}
+	}
+}
+
+// adapterSubnet 解析第 i 个地址及其掩码/前缀，返回所在子网
+// 地址可以带前缀（如 "2001:db8::1/64"），掩码可以是点分掩码或前缀长度
+func adapterSubnet(address string, masks []string, i int) (*net.IPNet, error) {
+	if strings.Contains(address, "/") {
+		ip, subnet, err := net.ParseCIDR(stripZone(address))
+		if err != nil {
+			return nil, fmt.Errorf("地址无效: %q", address)
+		}
+		subnet.IP = ip
+		return subnet, nil
+	}
+
+	ip := parseAddress(address)
+	if ip == nil {
+		return nil, fmt.Errorf("地址无效: %q", address)
+	}
+	if i >= len(masks) {
+		return nil, nil
+	}
+
+	mask, err := parseMask(masks[i], ip.To4() != nil)
+	if err != nil {
+		return nil, fmt.Errorf("地址 %s 的%v", address, err)
+	}
+	return &net.IPNet{IP: ip, Mask: mask}, nil
+}
+
+// parseMask 解析点分掩码或前缀长度，并检查与地址族是否一致
+func parseMask(value string, ipv4 bool) (net.IPMask, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "/")
+
+	bits := 128
+	if ipv4 {
+		bits = 32
+	}
+
+	if prefix, err := strconv.Atoi(value); err == nil {
+		if prefix < 0 || prefix > bits {
+			return nil, fmt.Errorf("前缀长度 %d 超出范围", prefix)
+		}
+		return net.CIDRMask(prefix, bits), nil
+	}
+
+	ip := net.ParseIP(value)
+	if ip == nil || ip.To4() == nil {
+		return nil, fmt.Errorf("子网掩码无效: %q", value)
+	}
+	if !ipv4 {
+		return nil, fmt.Errorf("IPv6 地址不能使用点分子网掩码 %q", value)
+	}
+	mask := net.IPMask(ip.To4())
+	if ones, _ := mask.Size(); ones == 0 && !ip.Equal(net.IPv4zero) {
+		return nil, fmt.Errorf("子网掩码不连续: %q", value)
+	}
+	return mask, nil
+}
+
+// validateProxy 校验代理配置
+func (v *validator) validateProxy(proxy types.ProxyConfig) {
+	if proxy.Enabled && proxy.Server == "" {
+		v.addf("代理已启用但未配置代理服务器")
+	}
+	if proxy.Server != "" {
+		if err := validateProxyServer(proxy.Server); err != nil {
+			v.addf("代理服务器无效: %v", err)
+		}
+	}
+	if proxy.AutoConfigURL != "" {
+		u, err := url.Parse(proxy.AutoConfigURL)
+		if err != nil || u.Host == "" && u.Scheme != "file" {
+			v.addf("自动配置脚本地址无效: %q", proxy.AutoConfigURL)
+		} else if u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "file" {
+			v.addf("自动配置脚本地址协议不受支持: %q", proxy.AutoConfigURL)
+		}
+	}
+}
+
+// validateProxyServer 校验 "host:port" 或 "http=host:port;https=host:port" 形式的代理地址
+func validateProxyServer(server string) error {
+	for _, part := range strings.Split(server, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if idx := strings.Index(part, "="); idx != -1 {
+			if strings.TrimSpace(part[:idx]) == "" {
+				return fmt.Errorf("缺少协议名: %q", part)
+			}
+			part = part[idx+1:]
+		}
+
+		host, port, err := net.SplitHostPort(part)
+		if err != nil {
+			// 未指定端口时 WinINet 使用默认端口，只需要主机名有效
+			if strings.Contains(part, ":") && net.ParseIP(part) == nil {
+				return fmt.Errorf("格式无效: %q", part)
+			}
+			host, port = part, ""
+		}
+		if host == "" || strings.ContainsAny(host, " \t/") {
+			return fmt.Errorf("主机名无效: %q", part)
+		}
+		if port != "" {
+			n, err := strconv.Atoi(port)
+			if err != nil || n < 1 || n > 65535 {
+				return fmt.Errorf("端口无效: %q", part)
+			}
+		}
+	}
+	return nil
+}
+
+// parseAddress 解析 IPv4/IPv6 地址（允许 IPv6 区域标识如 %12）
+func parseAddress(value string) net.IP {
+	return net.ParseIP(stripZone(strings.TrimSpace(value)))
+}
+
+// stripZone 去除 IPv6 区域标识
+func stripZone(value string) string {
+	if idx := strings.Index(value, "%"); idx != -1 {
+		if slash := strings.Index(value, "/"); slash > idx {
+			return value[:idx] + value[slash:]
+		}
+		return value[:idx]
+	}
+	return value
+}
+
+// inAnySubnet 检查地址是否属于任一子网
+func inAnySubnet(ip net.IP, subnets []*net.IPNet) bool {
+	for _, subnet := range subnets {
+		if subnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
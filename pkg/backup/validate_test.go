@@ -0,0 +1,205 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"network-rescue-toolkit/pkg/types"
+)
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	config := types.NetworkConfig{
+		Adapters:   []types.AdapterConfig{{Name: "以太网", DHCPEnabled: true}},
+		DNSServers: []string{},
+	}
+
+	data, err := encodeEnvelope(config)
+	if err != nil {
+		t.Fatalf("encodeEnvelope 失败: %v", err)
+	}
+
+	var envelope Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		t.Fatalf("解析信封失败: %v", err)
+	}
+	if envelope.SchemaVersion != CurrentSchemaVersion || len(envelope.SHA256) != 64 {
+		t.Errorf("信封字段不符: %+v", envelope)
+	}
+
+	decoded, err := decodeEnvelope(data)
+	if err != nil {
+		t.Fatalf("decodeEnvelope 失败: %v", err)
+	}
+	if len(decoded.Adapters) != 1 || decoded.Adapters[0].Name != "以太网" {
+		t.Errorf("解码结果不符: %+v", decoded)
+	}
+}
+
+func TestEnvelopeDetectsTampering(t *testing.T) {
+	data, err := encodeEnvelope(types.NetworkConfig{HostsContent: "127.0.0.1 localhost"})
+	if err != nil {
+		t.Fatalf("encodeEnvelope 失败: %v", err)
+	}
+
+	tampered := strings.Replace(string(data), "127.0.0.1 localhost", "1.2.3.4 www.baidu.com", 1)
+	if _, err := decodeEnvelope([]byte(tampered)); err == nil {
+		t.Error("被修改的备份应校验失败")
+	}
+}
+
+func TestEnvelopeRejectsNewerSchema(t *testing.T) {
+	payload := []byte(`{"adapters":[]}`)
+	envelope := Envelope{SchemaVersion: CurrentSchemaVersion + 1, SHA256: payloadHash(payload), Payload: payload}
+	data, _ := json.Marshal(envelope)
+
+	if _, err := decodeEnvelope(data); err == nil {
+		t.Error("更高版本的备份应被拒绝")
+	}
+}
+
+func TestLegacyBackupMigrates(t *testing.T) {
+	m, _, _ := newFixtureManager(t)
+
+	// 版本 0：没有信封，也没有汇总 DNS
+	legacy := `{
+  "adapters": [{"name": "以太网", "dhcpEnabled": false, "ipAddresses": ["192.168.1.10"], "subnetMasks": ["255.255.255.0"], "gateways": ["192.168.1.1"], "dnsServers": ["223.5.5.5"]}],
+  "proxySettings": {"enabled": false, "server": "", "port": 0, "bypassList": ""},
+  "hostsContent": ""
+}`
+	path := filepath.Join(m.backupDir, "backup_20250101_120000.json")
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatalf("写入旧版备份失败: %v", err)
+	}
+
+	if err := m.ValidateBackup(path); err != nil {
+		t.Fatalf("旧版备份应通过校验: %v", err)
+	}
+	config, _ := m.loadConfig(path)
+	if len(config.DNSServers) != 1 || config.DNSServers[0] != "223.5.5.5" {
+		t.Errorf("迁移后 DNS 汇总不符: %v", config.DNSServers)
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  types.NetworkConfig
+		wantErr string
+	}{
+		{
+			name: "有效的双栈静态配置",
+			config: types.NetworkConfig{
+				Adapters: []types.AdapterConfig{{
+					Name:        "以太网",
+					IPAddresses: []string{"192.168.1.10", "2001:db8::10/64"},
+					SubnetMasks: []string{"255.255.255.0", "64"},
+					Gateways:    []string{"fe80::1%12", "192.168.1.1"},
+					DNSServers:  []string{"223.5.5.5", "2400:3200::1"},
+				}},
+				ProxySettings: types.ProxyConfig{Enabled: true, Server: "http=127.0.0.1:7890;https=127.0.0.1:7890"},
+			},
+		},
+		{
+			name: "无效 IP",
+			config: types.NetworkConfig{Adapters: []types.AdapterConfig{{
+				Name: "以太网", IPAddresses: []string{"192.168.1.300"}, SubnetMasks: []string{"255.255.255.0"},
+			}}},
+			wantErr: "地址无效",
+		},
+		{
+			name: "不连续掩码",
+			config: types.NetworkConfig{Adapters: []types.AdapterConfig{{
+				Name: "以太网", IPAddresses: []string{"192.168.1.10"}, SubnetMasks: []string{"255.0.255.0"},
+			}}},
+			wantErr: "不连续",
+		},
+		{
+			name: "IPv6 使用点分掩码",
+			config: types.NetworkConfig{Adapters: []types.AdapterConfig{{
+				Name: "以太网", IPAddresses: []string{"2001:db8::10"}, SubnetMasks: []string{"255.255.255.0"},
+			}}},
+			wantErr: "点分子网掩码",
+		},
+		{
+			name: "缺少掩码",
+			config: types.NetworkConfig{Adapters: []types.AdapterConfig{{
+				Name: "以太网", IPAddresses: []string{"192.168.1.10"},
+			}}},
+			wantErr: "不一致",
+		},
+		{
+			name: "网关不在子网内",
+			config: types.NetworkConfig{Adapters: []types.AdapterConfig{{
+				Name: "以太网", IPAddresses: []string{"192.168.1.10"}, SubnetMasks: []string{"255.255.255.0"},
+				Gateways: []string{"192.168.2.1"},
+			}}},
+			wantErr: "不在任何已配置的子网内",
+		},
+		{
+			name: "DHCP 适配器不校验网关子网",
+			config: types.NetworkConfig{Adapters: []types.AdapterConfig{{
+				Name: "WLAN", DHCPEnabled: true, Gateways: []string{"10.0.0.1"},
+			}}},
+		},
+		{
+			name:    "代理端口无效",
+			config:  types.NetworkConfig{ProxySettings: types.ProxyConfig{Server: "127.0.0.1:99999"}},
+			wantErr: "端口无效",
+		},
+		{
+			name:    "代理格式无效",
+			config:  types.NetworkConfig{ProxySettings: types.ProxyConfig{Server: "a:1:2"}},
+			wantErr: "格式无效",
+		},
+		{
+			name:    "启用代理但没有服务器",
+			config:  types.NetworkConfig{ProxySettings: types.ProxyConfig{Enabled: true}},
+			wantErr: "未配置代理服务器",
+		},
+		{
+			name:    "PAC 地址无效",
+			config:  types.NetworkConfig{ProxySettings: types.ProxyConfig{AutoConfigURL: "ftp://x/wpad.dat"}},
+			wantErr: "协议不受支持",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateConfig(tc.config)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("期望通过校验，实际: %v", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("期望包含 %q 的校验错误，实际: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRestoreRejectsInvalidBackup(t *testing.T) {
+	m, _, hostsPath, _ := newRestoreManager(t)
+
+	path, err := m.saveConfig(types.NetworkConfig{
+		ProxySettings: types.ProxyConfig{Enabled: true, Server: "bad host:80"},
+		HostsContent:  "# clean\n",
+	}, BackupOptions{})
+	if err != nil {
+		t.Fatalf("保存备份失败: %v", err)
+	}
+
+	if _, err := m.RestoreBackup(path); err == nil {
+		t.Fatal("无效备份不应被还原")
+	}
+	if content, _ := os.ReadFile(hostsPath); string(content) != "127.0.0.1 localhost\n" {
+		t.Errorf("HOSTS 不应被修改: %q", content)
+	}
+}
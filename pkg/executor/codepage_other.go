@@ -0,0 +1,8 @@
+//go:build !windows
+
+package executor
+
+// consoleCodePage 非 Windows 平台的命令行统一使用 UTF-8
+func consoleCodePage() uint32 {
+	return 65001
+}
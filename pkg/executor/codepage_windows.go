@@ -0,0 +1,21 @@
+//go:build windows
+
+package executor
+
+import "golang.org/x/sys/windows"
+
+var (
+	kernel32               = windows.NewLazySystemDLL("kernel32.dll")
+	procGetConsoleOutputCP = kernel32.NewProc("GetConsoleOutputCP")
+	procGetOEMCP           = kernel32.NewProc("GetOEMCP")
+)
+
+// consoleCodePage 返回子进程控制台使用的代码页
+// 图形界面程序没有控制台，命令行工具会使用系统 OEM 代码页输出
+func consoleCodePage() uint32 {
+	if cp, _, _ := procGetConsoleOutputCP.Call(); cp != 0 {
+		return uint32(cp)
+	}
+	cp, _, _ := procGetOEMCP.Call()
+	return uint32(cp)
+}
@@ -5,11 +5,9 @@ import (
 	"context"
 	"os/exec"
 	"strings"
-	"syscall"
 	"time"
 
-	"golang.org/x/text/encoding/simplifiedchinese"
-	"golang.org/x/text/transform"
+	"network-rescue-toolkit/pkg/audit"
 )
 
 // CommandResult 命令执行结果
@@ -27,7 +25,8 @@ func (r *CommandResult) IsSuccess() bool {
 
 // CommandExecutor 命令执行器
 type CommandExecutor struct {
-	timeout time.Duration
+	timeout  time.Duration
+	encoding Encoding
 }
 
 // NewCommandExecutor 创建命令执行器
@@ -37,11 +36,16 @@ func NewCommandExecutor() *CommandExecutor {
 	}
 }
 
-// SetTimeout 设置超时时间
+// SetTimeout 设置默认超时时间
 func (e *CommandExecutor) SetTimeout(timeout time.Duration) {
 	e.timeout = timeout
 }
 
+// SetEncoding 指定命令输出的编码（默认 EncodingAuto，按控制台代码页自动识别）
+func (e *CommandExecutor) SetEncoding(enc Encoding) {
+	e.encoding = enc
+}
+
 // Execute 执行命令
 func (e *CommandExecutor) Execute(ctx context.Context, name string, args ...string) CommandResult {
 	return e.executeInternal(ctx, name, args, false)
@@ -56,9 +60,14 @@ func (e *CommandExecutor) ExecuteAsAdmin(ctx context.Context, name string, args
 // executeInternal 内部执行命令
 func (e *CommandExecutor) executeInternal(ctx context.Context, name string, args []string, asAdmin bool) CommandResult {
 	result := CommandResult{}
+	start := time.Now()
+	defer func() { auditCommand(ctx, name, args, start, result) }()
 
-	// 创建带超时的上下文
-	execCtx, cancel := context.WithTimeout(ctx, e.timeout)
+	// 创建带超时的上下文（调用方已设置截止时间时以调用方为准）
+	execCtx, cancel := ctx, context.CancelFunc(func() {})
+	if _, ok := ctx.Deadline(); !ok {
+		execCtx, cancel = context.WithTimeout(ctx, e.timeout)
+	}
 	defer cancel()
 
 	var cmd *exec.Cmd
@@ -71,9 +80,7 @@ func (e *CommandExecutor) executeInternal(ctx context.Context, name string, args
 	}
 
 	// 隐藏命令窗口
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		HideWindow: true,
-	}
+	hideWindow(cmd)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -81,9 +88,9 @@ func (e *CommandExecutor) executeInternal(ctx context.Context, name string, args
 
 	err := cmd.Run()
 
-	// 转换 GBK 编码到 UTF-8（Windows 命令行默认 GBK）
-	result.Stdout = decodeGBK(stdout.Bytes())
-	result.Stderr = decodeGBK(stderr.Bytes())
+	// 按控制台代码页转换为 UTF-8
+	result.Stdout = strings.TrimSpace(Decode(stdout.Bytes(), e.encoding))
+	result.Stderr = strings.TrimSpace(Decode(stderr.Bytes(), e.encoding))
 
 	if err != nil {
 		result.Error = err
@@ -97,12 +104,22 @@ func (e *CommandExecutor) executeInternal(ctx context.Context, name string, args
 	return result
 }
 
-// decodeGBK 将 GBK 编码转换为 UTF-8
-func decodeGBK(data []byte) string {
-	reader := transform.NewReader(bytes.NewReader(data), simplifiedchinese.GBK.NewDecoder())
-	buf := new(bytes.Buffer)
-	buf.ReadFrom(reader)
-	return strings.TrimSpace(buf.String())
+// auditCommand 将命令执行记录写入审计日志
+func auditCommand(ctx context.Context, name string, args []string, start time.Time, result CommandResult) {
+	entry := audit.Entry{
+		Time:       start,
+		Component:  audit.ComponentFrom(ctx),
+		Action:     audit.ActionExec,
+		Target:     name,
+		Args:       args,
+		ExitCode:   result.ExitCode,
+		DurationMs: time.Since(start).Milliseconds(),
+		Output:     strings.TrimSpace(result.Stdout + "\n" + result.Stderr),
+	}
+	if result.Error != nil {
+		entry.Error = result.Error.Error()
+	}
+	audit.Default().Log(entry)
 }
 
 // ExecuteNetsh 执行 netsh 命令
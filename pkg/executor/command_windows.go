@@ -0,0 +1,15 @@
+//go:build windows
+
+package executor
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// hideWindow 隐藏命令窗口
+func hideWindow(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow: true,
+	}
+}
@@ -0,0 +1,169 @@
+package executor
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Encoding 命令输出的字符编码
+type Encoding string
+
+const (
+	EncodingAuto     Encoding = ""             // 根据输出内容和控制台代码页自动识别
+	EncodingGBK      Encoding = "gbk"          // 简体中文（代码页 936）
+	EncodingBig5     Encoding = "big5"         // 繁体中文（代码页 950）
+	EncodingShiftJIS Encoding = "shift_jis"    // 日文（代码页 932）
+	EncodingUTF8     Encoding = "utf-8"        // UTF-8（代码页 65001）
+	EncodingUTF16LE  Encoding = "utf-16le"     // UTF-16LE（代码页 1200）
+	EncodingCP437    Encoding = "cp437"        // 英文 OEM（代码页 437）
+	EncodingCP850    Encoding = "cp850"        // 西欧 OEM（代码页 850）
+	EncodingCP1252   Encoding = "windows-1252" // 西欧 ANSI（代码页 1252）
+	EncodingLatin1   Encoding = "iso-8859-1"   // 按字节原样转换，用于不支持的代码页
+)
+
+// codePageEncodings 代码页与编码的对应关系
+var codePageEncodings = map[uint32]Encoding{
+	936:   EncodingGBK,
+	950:   EncodingBig5,
+	932:   EncodingShiftJIS,
+	65001: EncodingUTF8,
+	1200:  EncodingUTF16LE,
+	437:   EncodingCP437,
+	850:   EncodingCP850,
+	1252:  EncodingCP1252,
+	28591: EncodingLatin1,
+}
+
+// EncodingForCodePage 返回代码页对应的编码，不支持的代码页返回 EncodingAuto
+func EncodingForCodePage(codePage uint32) Encoding {
+	return codePageEncodings[codePage]
+}
+
+// ParseEncoding 解析编码名称或代码页编号（如 "gbk"、"936"、"utf-8"）
+func ParseEncoding(name string) (Encoding, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == "auto" {
+		return EncodingAuto, true
+	}
+	if codePage, err := strconv.ParseUint(name, 10, 32); err == nil {
+		enc, ok := codePageEncodings[uint32(codePage)]
+		return enc, ok
+	}
+
+	switch name {
+	case "gbk", "gb2312", "cp936":
+		return EncodingGBK, true
+	case "big5", "cp950":
+		return EncodingBig5, true
+	case "shift_jis", "shift-jis", "sjis", "cp932":
+		return EncodingShiftJIS, true
+	case "utf-8", "utf8", "cp65001":
+		return EncodingUTF8, true
+	case "utf-16le", "utf16le", "utf-16", "unicode":
+		return EncodingUTF16LE, true
+	case "cp437", "ibm437":
+		return EncodingCP437, true
+	case "cp850", "ibm850":
+		return EncodingCP850, true
+	case "windows-1252", "cp1252":
+		return EncodingCP1252, true
+	case "iso-8859-1", "latin1":
+		return EncodingLatin1, true
+	}
+	return EncodingAuto, false
+}
+
+// decoder 返回编码对应的解码器
+func (enc Encoding) decoder() *encoding.Decoder {
+	switch enc {
+	case EncodingGBK:
+		return simplifiedchinese.GBK.NewDecoder()
+	case EncodingBig5:
+		return traditionalchinese.Big5.NewDecoder()
+	case EncodingShiftJIS:
+		return japanese.ShiftJIS.NewDecoder()
+	case EncodingUTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewDecoder()
+	case EncodingCP437:
+		return charmap.CodePage437.NewDecoder()
+	case EncodingCP850:
+		return charmap.CodePage850.NewDecoder()
+	case EncodingCP1252:
+		return charmap.Windows1252.NewDecoder()
+	case EncodingLatin1:
+		return charmap.ISO8859_1.NewDecoder()
+	default:
+		return nil
+	}
+}
+
+// DetectEncoding 根据输出内容识别编码
+// 带 BOM 或形如 UTF-16LE 的输出直接识别；合法的 UTF-8 视为 UTF-8；其余按控制台代码页处理，
+// 不支持的代码页按字节原样转换（Latin-1），不会当作中文解码
+func DetectEncoding(data []byte, fallback Encoding) Encoding {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}), looksLikeUTF16LE(data):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}), utf8.Valid(data):
+		return EncodingUTF8
+	}
+
+	if fallback == EncodingAuto || fallback == EncodingUTF8 {
+		return encodingForConsole(consoleCodePage())
+	}
+	return fallback
+}
+
+// encodingForConsole 返回非 UTF-8 输出按控制台代码页解码时使用的编码
+func encodingForConsole(codePage uint32) Encoding {
+	enc := EncodingForCodePage(codePage)
+	switch {
+	case enc == EncodingAuto && codePage != 0:
+		return EncodingLatin1
+	case enc == EncodingAuto || enc == EncodingUTF8:
+		// 无法获取代码页，或控制台为 UTF-8 但输出不是 UTF-8 时，沿用 Windows 中文系统的默认编码
+		return EncodingGBK
+	}
+	return enc
+}
+
+// looksLikeUTF16LE 判断无 BOM 的输出是否为 UTF-16LE（ASCII 字符的高字节为 0）
+func looksLikeUTF16LE(data []byte) bool {
+	if len(data) < 4 || len(data)%2 != 0 {
+		return false
+	}
+	zeros := 0
+	for i := 1; i < len(data); i += 2 {
+		if data[i] == 0 {
+			zeros++
+		}
+	}
+	return zeros*2 >= len(data)/2
+}
+
+// Decode 将命令输出转换为 UTF-8 字符串
+// enc 为 EncodingAuto 时自动识别；指定编码时仍会识别 BOM 和 UTF-16LE 输出
+func Decode(data []byte, enc Encoding) string {
+	if enc == EncodingAuto || (enc != EncodingUTF16LE && looksLikeUTF16LE(data)) {
+		enc = DetectEncoding(data, enc)
+	}
+
+	decoder := enc.decoder()
+	if decoder == nil {
+		return strings.TrimPrefix(string(data), "\uFEFF")
+	}
+	decoded, err := decoder.Bytes(data)
+	if err != nil {
+		return string(data)
+	}
+	return string(decoded)
+}
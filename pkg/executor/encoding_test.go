@@ -0,0 +1,136 @@
+package executor
+
+import (
+	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func mustEncode(t *testing.T, enc encoding.Encoding, s string) []byte {
+	t.Helper()
+	data, err := enc.NewEncoder().Bytes([]byte(s))
+	if err != nil {
+		t.Fatalf("编码失败: %v", err)
+	}
+	return data
+}
+
+func TestDecodeExplicitEncodings(t *testing.T) {
+	cases := []struct {
+		name string
+		enc  Encoding
+		text string
+		src  encoding.Encoding
+	}{
+		{"GBK", EncodingGBK, "以太网适配器 以太网:", simplifiedchinese.GBK},
+		{"Big5", EncodingBig5, "乙太網路卡 乙太網路:", traditionalchinese.Big5},
+		{"Shift-JIS", EncodingShiftJIS, "イーサネット アダプター イーサネット:", japanese.ShiftJIS},
+		{"UTF-16LE", EncodingUTF16LE, "Ethernet adapter 以太网:", unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)},
+		{"CP850", EncodingCP850, "Ethernet-Adapter Verbindung über Funk: Größe", charmap.CodePage850},
+		{"Windows-1252", EncodingCP1252, "Carte Ethernet : connecté – réseau", charmap.Windows1252},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := Decode(mustEncode(t, c.src, c.text), c.enc); got != c.text {
+				t.Errorf("解码结果不符: %q", got)
+			}
+		})
+	}
+}
+
+func TestDecodeAutoDetect(t *testing.T) {
+	text := "以太网适配器 以太网:"
+
+	if got := Decode([]byte(text), EncodingAuto); got != text {
+		t.Errorf("UTF-8 输出应原样返回: %q", got)
+	}
+	if got := Decode(append([]byte{0xEF, 0xBB, 0xBF}, text...), EncodingAuto); got != text {
+		t.Errorf("应去掉 UTF-8 BOM: %q", got)
+	}
+
+	utf16 := mustEncode(t, unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), "Windows IP Configuration")
+	if got := Decode(utf16, EncodingAuto); got != "Windows IP Configuration" {
+		t.Errorf("应识别带 BOM 的 UTF-16LE: %q", got)
+	}
+
+	// 指定了 GBK 但输出实际是 UTF-16LE（如 PowerShell 重定向）
+	utf16 = mustEncode(t, unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), "Pinging 8.8.8.8")
+	if got := Decode(utf16, EncodingGBK); got != "Pinging 8.8.8.8" {
+		t.Errorf("应识别无 BOM 的 UTF-16LE: %q", got)
+	}
+
+	// 无法识别为 UTF-8 时按代码页解码，非 Windows 平台回退到 GBK
+	if got := Decode(mustEncode(t, simplifiedchinese.GBK, text), EncodingAuto); got != text {
+		t.Errorf("GBK 输出解码结果不符: %q", got)
+	}
+}
+
+func TestParseEncoding(t *testing.T) {
+	cases := map[string]Encoding{
+		"":         EncodingAuto,
+		"936":      EncodingGBK,
+		"950":      EncodingBig5,
+		"SJIS":     EncodingShiftJIS,
+		"65001":    EncodingUTF8,
+		"utf-16le": EncodingUTF16LE,
+		" GB2312 ": EncodingGBK,
+		"437":      EncodingCP437,
+		"850":      EncodingCP850,
+		"cp1252":   EncodingCP1252,
+		"latin1":   EncodingLatin1,
+	}
+	for name, want := range cases {
+		if got, ok := ParseEncoding(name); !ok || got != want {
+			t.Errorf("ParseEncoding(%q) = %q, %v", name, got, ok)
+		}
+	}
+	if _, ok := ParseEncoding("1251"); ok {
+		t.Error("不支持的代码页应返回 false")
+	}
+	if EncodingForCodePage(932) != EncodingShiftJIS || EncodingForCodePage(1251) != EncodingAuto {
+		t.Error("代码页映射不符")
+	}
+}
+
+func TestDecodeCP850Umlauts(t *testing.T) {
+	text := "Verbindungsspezifisches DNS-Suffix: Büro Größe Übertragung"
+	data := mustEncode(t, charmap.CodePage850, text)
+
+	enc, ok := ParseEncoding("850")
+	if !ok {
+		t.Fatal("应支持代码页 850")
+	}
+	if got := Decode(data, enc); got != text {
+		t.Errorf("CP850 解码结果不符: %q", got)
+	}
+	if enc := encodingForConsole(850); enc != EncodingCP850 {
+		t.Errorf("控制台代码页 850 应按 CP850 解码，实际 %q", enc)
+	}
+}
+
+func TestEncodingForConsole(t *testing.T) {
+	cases := map[uint32]Encoding{
+		936:   EncodingGBK,
+		437:   EncodingCP437,
+		1252:  EncodingCP1252,
+		1251:  EncodingLatin1, // 不支持的代码页按字节原样转换
+		65001: EncodingGBK,
+		0:     EncodingGBK,
+	}
+	for codePage, want := range cases {
+		if got := encodingForConsole(codePage); got != want {
+			t.Errorf("encodingForConsole(%d) = %q, want %q", codePage, got, want)
+		}
+	}
+
+	// Latin-1 转换不会把单字节输出拼成中文
+	if got := Decode([]byte("Gr\xf6\xdfe"), EncodingLatin1); got != "Größe" {
+		t.Errorf("Latin-1 转换结果不符: %q", got)
+	}
+}
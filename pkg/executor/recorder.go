@@ -0,0 +1,191 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"network-rescue-toolkit/pkg/audit"
+)
+
+// fixtureVersion 录制文件格式版本
+const fixtureVersion = 1
+
+// Recording 单条命令的录制结果（输出为已解码的 UTF-8 文本）
+type Recording struct {
+	Name       string    `json:"name"`
+	Args       []string  `json:"args"`
+	Stdout     string    `json:"stdout"`
+	Stderr     string    `json:"stderr"`
+	ExitCode   int       `json:"exitCode"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"durationMs"`
+	StartedAt  time.Time `json:"startedAt"`
+}
+
+// Fixture 录制文件
+type Fixture struct {
+	Version     int         `json:"version"`
+	RecordedAt  time.Time   `json:"recordedAt"`
+	MachineName string      `json:"machineName"`
+	Commands    []Recording `json:"commands"`
+}
+
+// Recorder 录制模式：执行真实命令，并把每次调用保存到录制文件
+type Recorder struct {
+	runner  Runner
+	path    string
+	fixture Fixture
+	err     error // 最近一次保存失败的错误，保存成功后清除
+	mu      sync.Mutex
+}
+
+// NewRecorder 创建录制执行器，录制结果写入 path
+func NewRecorder(runner Runner, path string) *Recorder {
+	machineName, _ := os.Hostname()
+	return &Recorder{
+		runner: runner,
+		path:   path,
+		fixture: Fixture{
+			Version:     fixtureVersion,
+			RecordedAt:  time.Now(),
+			MachineName: machineName,
+			Commands:    make([]Recording, 0),
+		},
+	}
+}
+
+// Execute 执行命令并录制结果
+func (r *Recorder) Execute(ctx context.Context, name string, args ...string) CommandResult {
+	start := time.Now()
+	result := r.runner.Execute(ctx, name, args...)
+	r.record(ctx, start, name, args, result)
+	return result
+}
+
+// ExecuteStream 以逐行输出的方式执行命令并记录完整结果
+func (r *Recorder) ExecuteStream(ctx context.Context, onLine LineHandler, name string, args ...string) CommandResult {
+	start := time.Now()
+	result := ExecuteStream(ctx, r.runner, onLine, name, args...)
+	r.record(ctx, start, name, args, result)
+	return result
+}
+
+// record 追加一条录制记录并保存，保存失败时写入审计日志，并由 Err、Save 和 Close 返回
+func (r *Recorder) record(ctx context.Context, start time.Time, name string, args []string, result CommandResult) {
+	recording := Recording{
+		Name:       name,
+		Args:       append([]string{}, args...),
+		Stdout:     result.Stdout,
+		Stderr:     result.Stderr,
+		ExitCode:   result.ExitCode,
+		DurationMs: time.Since(start).Milliseconds(),
+		StartedAt:  start,
+	}
+	if result.Error != nil {
+		recording.Error = result.Error.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fixture.Commands = append(r.fixture.Commands, recording)
+	// 每条命令后立即保存，程序异常退出时也不会丢失录制内容
+	saveStart := time.Now()
+	if err := r.save(); err != nil {
+		audit.Default().LogWrite(ctx, audit.ActionFileWrite, r.path, nil, saveStart, err)
+	}
+}
+
+// Recordings 返回已录制的命令
+func (r *Recorder) Recordings() []Recording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Recording(nil), r.fixture.Commands...)
+}
+
+// Err 返回最近一次保存录制文件失败的错误，录制文件已完整保存时返回 nil
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Save 保存录制文件
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.save()
+}
+
+// Close 结束录制并保存录制文件，返回保存失败的错误
+func (r *Recorder) Close() error {
+	return r.Save()
+}
+
+// save 保存录制文件并记录结果（调用方需持有锁）
+// 每次保存都写入全部录制内容，成功后之前的保存失败不再影响录制文件
+func (r *Recorder) save() error {
+	r.err = r.writeFixture()
+	return r.err
+}
+
+// writeFixture 先写临时文件再替换，避免保存中断时留下不完整的录制文件
+func (r *Recorder) writeFixture() error {
+	data, err := json.MarshalIndent(r.fixture, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化录制文件失败: %w", err)
+	}
+
+	tmpPath := r.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("保存录制文件失败: %w", err)
+	}
+	if err := os.Rename(tmpPath, r.path); err != nil {
+		return fmt.Errorf("保存录制文件失败: %w", err)
+	}
+	return nil
+}
+
+// LoadFixture 读取录制文件
+func LoadFixture(path string) (Fixture, error) {
+	var fixture Fixture
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fixture, fmt.Errorf("读取录制文件失败: %w", err)
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return fixture, fmt.Errorf("解析录制文件失败: %w", err)
+	}
+	if fixture.Version > fixtureVersion {
+		return fixture, fmt.Errorf("录制文件版本 %d 高于当前支持的版本 %d", fixture.Version, fixtureVersion)
+	}
+	return fixture, nil
+}
+
+// NewReplayer 创建回放执行器：按命令行返回录制的结果
+// 同一命令录制了多次时按录制顺序依次返回，之后重复最后一次的结果
+func NewReplayer(path string) (*FakeRunner, error) {
+	fixture, err := LoadFixture(path)
+	if err != nil {
+		return nil, err
+	}
+
+	replayer := NewFakeRunner()
+	for _, rec := range fixture.Commands {
+		result := CommandResult{
+			ExitCode: rec.ExitCode,
+			Stdout:   rec.Stdout,
+			Stderr:   rec.Stderr,
+		}
+		if rec.Error != "" {
+			result.Error = errors.New(rec.Error)
+		}
+		replayer.On(CommandLine(rec.Name, rec.Args...), result)
+	}
+	return replayer, nil
+}
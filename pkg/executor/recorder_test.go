@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recording.json")
+	source := NewFakeRunner().
+		On("ping -n 4 www.baidu.com",
+			CommandResult{ExitCode: 1, Stdout: "请求超时。"},
+			CommandResult{Stdout: "来自 110.242.68.66 的回复: 字节=32 时间=12ms TTL=52"}).
+		OnFailure("netsh winsock reset", 1, "请求的操作需要提升。")
+
+	ctx := context.Background()
+	recorder := NewRecorder(source, path)
+	first := recorder.Execute(ctx, "ping", "-n", "4", "www.baidu.com")
+	second := recorder.Execute(ctx, "ping", "-n", "4", "www.baidu.com")
+	failed := recorder.Execute(ctx, "netsh", "winsock", "reset")
+
+	recordings := recorder.Recordings()
+	if len(recordings) != 3 || recordings[0].Name != "ping" || len(recordings[0].Args) != 3 {
+		t.Fatalf("录制内容不符: %+v", recordings)
+	}
+
+	fixture, err := LoadFixture(path)
+	if err != nil {
+		t.Fatalf("LoadFixture 失败: %v", err)
+	}
+	if len(fixture.Commands) != 3 || fixture.MachineName == "" {
+		t.Errorf("录制文件不符: %+v", fixture)
+	}
+
+	replayer, err := NewReplayer(path)
+	if err != nil {
+		t.Fatalf("NewReplayer 失败: %v", err)
+	}
+
+	for i, want := range []CommandResult{first, second} {
+		got := replayer.Execute(ctx, "ping", "-n", "4", "www.baidu.com")
+		if got.ExitCode != want.ExitCode || got.Stdout != want.Stdout {
+			t.Errorf("第 %d 次回放结果不符: got %+v, want %+v", i+1, got, want)
+		}
+	}
+
+	got := replayer.Execute(ctx, "netsh", "winsock", "reset")
+	if got.IsSuccess() || got.ExitCode != failed.ExitCode || got.Stderr != failed.Stderr || got.Error == nil {
+		t.Errorf("失败命令回放结果不符: %+v", got)
+	}
+
+	if r := replayer.Execute(ctx, "ipconfig", "/all"); r.IsSuccess() {
+		t.Error("未录制的命令应返回失败")
+	}
+}
+
+func TestRecorderReportsSaveError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "missing", "recording.json")
+	recorder := NewRecorder(NewFakeRunner().OnOutput("ipconfig /all", "Windows IP 配置"), path)
+
+	recorder.Execute(context.Background(), "ipconfig", "/all")
+	if recorder.Err() == nil {
+		t.Fatal("录制文件所在目录不存在时应返回保存错误")
+	}
+	if err := recorder.Close(); err == nil {
+		t.Error("Close 应返回保存错误")
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "missing"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Close(); err != nil || recorder.Err() != nil {
+		t.Errorf("目录创建后应保存成功: %v", err)
+	}
+	if fixture, err := LoadFixture(path); err != nil || len(fixture.Commands) != 1 {
+		t.Errorf("重新保存后应包含全部录制内容: %+v %v", fixture, err)
+	}
+}
@@ -0,0 +1,116 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Runner 命令执行接口
+// 检查器、修复器通过注入 Runner 执行外部命令，测试时可替换为 FakeRunner
+type Runner interface {
+	// Execute 执行命令并返回结果
+	Execute(ctx context.Context, name string, args ...string) CommandResult
+}
+
+// CommandLine 将命令名和参数拼接为命令行（用于匹配预设结果和记录）
+func CommandLine(name string, args ...string) string {
+	return strings.Join(append([]string{name}, args...), " ")
+}
+
+// ErrUnexpectedCommand 执行了未预设的命令
+var ErrUnexpectedCommand = errors.New("未预设的命令")
+
+// FakeRunner 可编排的命令执行器，按命令行返回预设的输出和退出码
+type FakeRunner struct {
+	responses map[string][]CommandResult
+	fallback  *CommandResult
+	calls     []string
+	mu        sync.Mutex
+}
+
+// NewFakeRunner 创建可编排的命令执行器
+func NewFakeRunner() *FakeRunner {
+	return &FakeRunner{
+		responses: make(map[string][]CommandResult),
+		calls:     make([]string, 0),
+	}
+}
+
+// On 为命令行预设结果；预设多个结果时按调用顺序依次返回，最后一个结果会重复使用
+func (f *FakeRunner) On(commandLine string, results ...CommandResult) *FakeRunner {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[commandLine] = append(f.responses[commandLine], results...)
+	return f
+}
+
+// OnOutput 为命令行预设成功输出
+func (f *FakeRunner) OnOutput(commandLine, stdout string) *FakeRunner {
+	return f.On(commandLine, CommandResult{Stdout: stdout})
+}
+
+// OnFailure 为命令行预设失败结果
+func (f *FakeRunner) OnFailure(commandLine string, exitCode int, stderr string) *FakeRunner {
+	return f.On(commandLine, CommandResult{
+		ExitCode: exitCode,
+		Stderr:   stderr,
+		Error:    fmt.Errorf("exit status %d", exitCode),
+	})
+}
+
+// SetDefault 设置未预设命令的返回结果（默认返回 ErrUnexpectedCommand）
+func (f *FakeRunner) SetDefault(result CommandResult) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fallback = &result
+}
+
+// Execute 返回预设结果并记录调用
+func (f *FakeRunner) Execute(ctx context.Context, name string, args ...string) CommandResult {
+	commandLine := CommandLine(name, args...)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, commandLine)
+
+	if err := ctx.Err(); err != nil {
+		return CommandResult{ExitCode: -1, Error: err}
+	}
+
+	queue, ok := f.responses[commandLine]
+	if !ok || len(queue) == 0 {
+		if f.fallback != nil {
+			return *f.fallback
+		}
+		return CommandResult{
+			ExitCode: -1,
+			Error:    fmt.Errorf("%w: %s", ErrUnexpectedCommand, commandLine),
+		}
+	}
+
+	result := queue[0]
+	if len(queue) > 1 {
+		f.responses[commandLine] = queue[1:]
+	}
+	return result
+}
+
+// Calls 返回所有已执行的命令行
+func (f *FakeRunner) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// Called 检查命令行是否被执行过
+func (f *FakeRunner) Called(commandLine string) bool {
+	for _, call := range f.Calls() {
+		if call == commandLine {
+			return true
+		}
+	}
+	return false
+}
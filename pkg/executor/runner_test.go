@@ -0,0 +1,58 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFakeRunnerSequence(t *testing.T) {
+	fake := NewFakeRunner().
+		On("ping -n 1 host", CommandResult{ExitCode: 1}, CommandResult{Stdout: "ok"})
+
+	ctx := context.Background()
+	if r := fake.Execute(ctx, "ping", "-n", "1", "host"); r.ExitCode != 1 {
+		t.Errorf("第一次调用应返回第一个结果: %+v", r)
+	}
+	for i := 0; i < 2; i++ {
+		if r := fake.Execute(ctx, "ping", "-n", "1", "host"); !r.IsSuccess() || r.Stdout != "ok" {
+			t.Errorf("后续调用应重复最后一个结果: %+v", r)
+		}
+	}
+	if len(fake.Calls()) != 3 || !fake.Called("ping -n 1 host") {
+		t.Errorf("调用记录不符: %v", fake.Calls())
+	}
+}
+
+func TestFakeRunnerUnexpectedCommand(t *testing.T) {
+	fake := NewFakeRunner()
+
+	r := fake.Execute(context.Background(), "netsh", "winsock", "reset")
+	if r.IsSuccess() || !errors.Is(r.Error, ErrUnexpectedCommand) {
+		t.Errorf("未预设的命令应失败: %+v", r)
+	}
+
+	fake.SetDefault(CommandResult{})
+	if r := fake.Execute(context.Background(), "netsh", "winsock", "reset"); !r.IsSuccess() {
+		t.Errorf("设置默认结果后应成功: %+v", r)
+	}
+}
+
+func TestFakeRunnerCanceledContext(t *testing.T) {
+	fake := NewFakeRunner().OnOutput("ipconfig /all", "output")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if r := fake.Execute(ctx, "ipconfig", "/all"); r.IsSuccess() {
+		t.Errorf("已取消的上下文应返回失败: %+v", r)
+	}
+}
+
+func TestFakeRunnerFailure(t *testing.T) {
+	fake := NewFakeRunner().OnFailure("netsh int ip reset", 1, "拒绝访问")
+
+	r := fake.Execute(context.Background(), "netsh", "int", "ip", "reset")
+	if r.IsSuccess() || r.ExitCode != 1 || r.Stderr != "拒绝访问" {
+		t.Errorf("失败结果不符: %+v", r)
+	}
+}
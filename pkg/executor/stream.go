@@ -0,0 +1,124 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"golang.org/x/text/transform"
+)
+
+// LineHandler 逐行接收命令输出的回调
+type LineHandler func(line string)
+
+// StreamRunner 支持逐行输出的命令执行接口
+type StreamRunner interface {
+	Runner
+	// ExecuteStream 执行命令，每输出一行调用一次 onLine，结束后返回完整结果
+	ExecuteStream(ctx context.Context, onLine LineHandler, name string, args ...string) CommandResult
+}
+
+// ExecuteStream 以逐行输出的方式执行命令
+// runner 不支持流式执行时，等命令结束后再逐行回调
+func ExecuteStream(ctx context.Context, runner Runner, onLine LineHandler, name string, args ...string) CommandResult {
+	if streamer, ok := runner.(StreamRunner); ok {
+		return streamer.ExecuteStream(ctx, onLine, name, args...)
+	}
+
+	result := runner.Execute(ctx, name, args...)
+	emitLines(result.Stdout, onLine)
+	return result
+}
+
+// emitLines 将已完成的输出逐行回调
+func emitLines(output string, onLine LineHandler) {
+	if output == "" || onLine == nil {
+		return
+	}
+	for _, line := range strings.Split(output, "\n") {
+		onLine(strings.TrimRight(line, "\r"))
+	}
+}
+
+// ExecuteStream 执行命令并逐行回调标准输出
+func (e *CommandExecutor) ExecuteStream(ctx context.Context, onLine LineHandler, name string, args ...string) CommandResult {
+	result := CommandResult{}
+	start := time.Now()
+	defer func() { auditCommand(ctx, name, args, start, result) }()
+
+	execCtx, cancel := ctx, context.CancelFunc(func() {})
+	if _, ok := ctx.Deadline(); !ok {
+		execCtx, cancel = context.WithTimeout(ctx, e.timeout)
+	}
+	defer cancel()
+
+	cmd := exec.CommandContext(execCtx, name, args...)
+	hideWindow(cmd)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	// 通过管道转发输出；取消后子进程残留的后代进程可能仍占用管道，WaitDelay 到期后强制关闭
+	stdout, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Start(); err != nil {
+		result.ExitCode = -1
+		result.Error = err
+		return result
+	}
+
+	var err error
+	done := make(chan struct{})
+	go func() {
+		err = cmd.Wait()
+		writer.Close()
+		close(done)
+	}()
+
+	// UTF-16LE 的换行符占两个字节，需先整体转码再按行切分
+	var reader io.Reader = stdout
+	lineEncoding := e.encoding
+	if e.encoding == EncodingUTF16LE {
+		reader = transform.NewReader(stdout, e.encoding.decoder())
+		lineEncoding = EncodingUTF8
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(Decode(scanner.Bytes(), lineEncoding), "\r")
+		lines = append(lines, line)
+		if onLine != nil {
+			onLine(line)
+		}
+	}
+
+	// 扫描因超长行中断时继续读取剩余输出，避免子进程阻塞在写管道上
+	io.Copy(io.Discard, stdout)
+	<-done
+
+	result.Stdout = strings.TrimSpace(strings.Join(lines, "\n"))
+	result.Stderr = strings.TrimSpace(Decode(stderr.Bytes(), e.encoding))
+
+	if err != nil {
+		result.Error = err
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			result.ExitCode = exitErr.ExitCode()
+		} else {
+			result.ExitCode = -1
+		}
+	}
+	if ctxErr := execCtx.Err(); ctxErr != nil {
+		// 被取消或超时时返回上下文错误，便于调用方区分
+		result.Error = ctxErr
+	}
+
+	return result
+}
@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestExecuteStreamFallback(t *testing.T) {
+	fake := NewFakeRunner().OnOutput("ping -n 4 host", "来自 host 的回复\r\n来自 host 的回复")
+
+	var lines []string
+	result := ExecuteStream(context.Background(), fake, func(line string) {
+		lines = append(lines, line)
+	}, "ping", "-n", "4", "host")
+
+	if !result.IsSuccess() || len(lines) != 2 || lines[1] != "来自 host 的回复" {
+		t.Errorf("不支持流式执行时应在结束后逐行回调: %v %+v", lines, result)
+	}
+}
+
+func TestRecorderStream(t *testing.T) {
+	fake := NewFakeRunner().OnOutput("tracert host", "1 hop\n2 hop")
+	recorder := NewRecorder(fake, filepath.Join(t.TempDir(), "rec.json"))
+
+	count := 0
+	ExecuteStream(context.Background(), recorder, func(string) { count++ }, "tracert", "host")
+
+	if count != 2 || len(recorder.Recordings()) != 1 || recorder.Recordings()[0].Stdout != "1 hop\n2 hop" {
+		t.Errorf("录制器应转发逐行输出并记录完整结果: %d %+v", count, recorder.Recordings())
+	}
+}
+
+func TestCommandExecutorStreamCancel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("使用 sh 测试逐行输出")
+	}
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("缺少 sh")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var lines []string
+	start := time.Now()
+	result := NewCommandExecutor().ExecuteStream(ctx, func(line string) {
+		lines = append(lines, line)
+		// 收到第一行时命令仍在运行，取消后应立即结束
+		cancel()
+	}, "sh", "-c", "echo first; sleep 10; echo second")
+
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("取消后命令应立即结束")
+	}
+	if len(lines) != 1 || lines[0] != "first" {
+		t.Errorf("应在命令结束前收到第一行: %v", lines)
+	}
+	if result.Error != context.Canceled {
+		t.Errorf("取消后应返回 context.Canceled: %v", result.Error)
+	}
+}
@@ -0,0 +1,318 @@
+package ipconfig
+
+import (
+	"strings"
+
+	"network-rescue-toolkit/pkg/types"
+)
+
+// 解析不依赖字段名的显示语言：
+//   - 标题行顶格（适配器标题以冒号结尾），字段行缩进并以冒号分隔字段名和取值，续行只有一个地址
+//   - 字段名中各语言通用的 ASCII 词（DNS、DHCP、DHCPv6、IPv4、IPv6、WINS、NetBIOS）直接识别
+//   - 其余字段按取值形态和出现位置识别：物理地址、IPv4 地址后的子网掩码、租约时间、默认网关
+
+// adapterMarkers 标题行中适配器类型与名称之间的分隔词
+var adapterMarkers = []string{"adapter ", "适配器 ", "介面卡 ", "アダプター "}
+
+// Parse 解析 ipconfig /all 输出为适配器配置列表（仅 IPv4）
+func Parse(output string) []types.AdapterConfig {
+	report := ParseReport(output)
+	configs := make([]types.AdapterConfig, 0, len(report.Adapters))
+	for _, adapter := range report.Adapters {
+		configs = append(configs, adapter.Config())
+	}
+	return configs
+}
+
+// ParseReport 解析 ipconfig /all 的完整输出
+func ParseReport(output string) Report {
+	report := Report{Adapters: make([]Adapter, 0)}
+
+	var current *adapterParser
+	var global *globalParser
+	headers := 0
+
+	for _, raw := range strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+
+		if isHeader(raw) {
+			header := strings.TrimSpace(raw)
+			header = strings.TrimSpace(strings.TrimSuffix(header, ":"))
+			headers++
+
+			if current != nil {
+				report.Adapters = append(report.Adapters, current.adapter)
+			}
+			current, global = nil, nil
+
+			// 第一个标题为 "Windows IP 配置" / "Windows IP Configuration" 等全局信息
+			if headers == 1 && strings.HasPrefix(header, "Windows") {
+				global = &globalParser{report: &report}
+			} else {
+				current = newAdapterParser(header)
+			}
+			continue
+		}
+
+		label, value, isField := splitField(raw)
+		switch {
+		case current != nil && isField:
+			current.field(label, value)
+		case current != nil:
+			current.continuation(strings.TrimSpace(raw))
+		case global != nil && isField:
+			global.field(label, value)
+		case global != nil:
+			global.continuation(strings.TrimSpace(raw))
+		}
+	}
+
+	if current != nil {
+		report.Adapters = append(report.Adapters, current.adapter)
+	}
+	return report
+}
+
+// isHeader 判断是否为标题行（顶格输出）
+func isHeader(line string) bool {
+	return line[0] != ' ' && line[0] != '\t'
+}
+
+// splitField 拆分字段行为字段名和取值
+// 字段名不含冒号，第一个冒号即为分隔符；只有一个地址的行是多值字段的续行
+func splitField(line string) (label, value string, ok bool) {
+	trimmed := strings.TrimSpace(line)
+	if len(strings.Fields(trimmed)) == 1 && isIP(trimmed) {
+		return "", "", false
+	}
+
+	idx := strings.Index(line, ":")
+	if idx < 0 {
+		return "", "", false
+	}
+	label = strings.TrimSpace(strings.TrimRight(line[:idx], ". "))
+	return label, strings.TrimSpace(line[idx+1:]), true
+}
+
+// adapterName 从标题行提取适配器名称
+func adapterName(header string) string {
+	lower := strings.ToLower(header)
+	for _, marker := range adapterMarkers {
+		if idx := strings.Index(lower, marker); idx >= 0 {
+			return strings.TrimSpace(header[idx+len(marker):])
+		}
+	}
+	return header
+}
+
+// globalParser 解析全局信息段
+type globalParser struct {
+	report     *Report
+	fields     int
+	dnsFields  int
+	lastSearch bool
+}
+
+// field 处理全局信息段的字段行
+func (p *globalParser) field(label, value string) {
+	p.fields++
+	p.lastSearch = false
+	upper := strings.ToUpper(label)
+
+	switch {
+	case p.fields == 1:
+		p.report.HostName = value
+	case strings.Contains(upper, "DNS"):
+		// 依次为主 DNS 后缀、DNS 后缀搜索列表
+		p.dnsFields++
+		if p.dnsFields == 1 {
+			p.report.PrimaryDNSSuffix = value
+		} else {
+			p.lastSearch = true
+			if value != "" {
+				p.report.DNSSuffixSearchList = append(p.report.DNSSuffixSearchList, value)
+			}
+		}
+	}
+}
+
+// continuation 处理全局信息段的续行
+func (p *globalParser) continuation(value string) {
+	if p.lastSearch {
+		p.report.DNSSuffixSearchList = append(p.report.DNSSuffixSearchList, value)
+	}
+}
+
+// 可以有续行的多值字段
+const (
+	listNone = iota
+	listGateway
+	listDNS
+	listWINS
+)
+
+// adapterParser 解析单个适配器段
+type adapterParser struct {
+	adapter      Adapter
+	seenSuffix   bool
+	awaitingMask bool
+	leases       int
+	ipv6Label    string
+	list         int
+}
+
+// newAdapterParser 创建适配器段解析器
+func newAdapterParser(header string) *adapterParser {
+	return &adapterParser{
+		adapter: Adapter{
+			Name:   adapterName(header),
+			Header: header,
+		},
+	}
+}
+
+// field 处理适配器段的字段行
+func (p *adapterParser) field(label, value string) {
+	a := &p.adapter
+	upper := strings.ToUpper(label)
+	p.list = listNone
+
+	switch {
+	case strings.Contains(upper, "DHCPV6"):
+		if isDigits(value) {
+			a.DHCPv6IAID = value
+		} else {
+			a.DHCPv6ClientDUID = value
+		}
+
+	case strings.Contains(upper, "IPV6"):
+		p.addIPv6(label, value)
+
+	case strings.Contains(upper, "IPV4"):
+		if addr, _, ok := parseIP(value); ok && addr.Is4() {
+			a.IPv4 = append(a.IPv4, IPv4Address{Address: addr.String()})
+			p.awaitingMask = true
+		}
+
+	case strings.Contains(upper, "NETBIOS"):
+		a.NetBIOSEnabled, _ = parseBool(value)
+
+	case strings.Contains(upper, "WINS"):
+		p.list = listWINS
+		p.appendList(value)
+
+	case strings.Contains(upper, "DNS"):
+		if isIP(value) {
+			p.list = listDNS
+			p.appendList(value)
+		} else if !p.seenSuffix {
+			a.DNSSuffix = value
+			p.seenSuffix = true
+		}
+
+	case strings.Contains(upper, "DHCP"):
+		if addr, _, ok := parseIP(value); ok {
+			a.DHCPServer = addr.String()
+		} else if enabled, ok := parseBool(value); ok {
+			a.DHCPEnabled = enabled
+		}
+
+	default:
+		p.unlabeledField(value)
+	}
+
+	// 获得过租约的适配器一定启用了 DHCP（是/否 的取值无法识别时的兜底）
+	if a.DHCPServer != "" || a.LeaseObtained != "" {
+		a.DHCPEnabled = true
+	}
+}
+
+// unlabeledField 按取值形态和位置识别没有通用关键词的字段
+func (p *adapterParser) unlabeledField(value string) {
+	a := &p.adapter
+
+	if p.awaitingMask {
+		if prefix, ok := maskPrefixLength(value); ok {
+			last := &a.IPv4[len(a.IPv4)-1]
+			last.SubnetMask = value
+			last.PrefixLength = prefix
+			p.awaitingMask = false
+			return
+		}
+	}
+
+	switch {
+	case isMAC(value):
+		a.PhysicalAddress = strings.ToUpper(value)
+	case isDate(value):
+		// 依次为获得租约的时间、租约过期的时间
+		p.leases++
+		if p.leases == 1 {
+			a.LeaseObtained = value
+		} else {
+			a.LeaseExpires = value
+		}
+	case value == "" || isIP(value):
+		// 默认网关：可能为空，也可能只有续行
+		p.list = listGateway
+		p.appendList(value)
+	default:
+		if enabled, ok := parseBool(value); ok {
+			a.AutoconfigEnabled = enabled
+		} else if !p.seenSuffix {
+			// 断开连接的适配器在 DNS 后缀之前输出媒体状态
+			a.MediaDisconnected = true
+		} else if a.Description == "" {
+			a.Description = value
+		}
+	}
+}
+
+// addIPv6 记录 IPv6 地址
+// 全局地址中，字段名与第一个全局地址不同的是临时地址
+func (p *adapterParser) addIPv6(label, value string) {
+	addr, prefix, ok := parseIP(value)
+	if !ok || !addr.Is6() {
+		return
+	}
+
+	entry := IPv6Address{
+		Address:      addr.WithZone("").String(),
+		Zone:         addr.Zone(),
+		PrefixLength: prefix,
+		Scope:        ipv6Scope(addr),
+	}
+	if entry.Scope == ScopeGlobal {
+		if p.ipv6Label == "" {
+			p.ipv6Label = label
+		} else if label != p.ipv6Label {
+			entry.Scope = ScopeTemporary
+		}
+	}
+	p.adapter.IPv6 = append(p.adapter.IPv6, entry)
+}
+
+// appendList 向当前多值字段追加地址
+func (p *adapterParser) appendList(value string) {
+	addr, _, ok := parseIP(value)
+	if !ok {
+		return
+	}
+	a := &p.adapter
+
+	switch p.list {
+	case listGateway:
+		a.Gateways = append(a.Gateways, addr.String())
+	case listDNS:
+		a.DNSServers = append(a.DNSServers, addr.String())
+	case listWINS:
+		a.WINSServers = append(a.WINSServers, addr.String())
+	}
+}
+
+// continuation 处理续行（多值字段的后续地址）
+func (p *adapterParser) continuation(value string) {
+	p.appendList(value)
+}
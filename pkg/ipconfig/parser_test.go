@@ -0,0 +1,71 @@
+package ipconfig
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "重新生成 golden 文件")
+
+// TestParseReportGolden 各语言 ipconfig /all 输出与 golden 文件比对
+func TestParseReportGolden(t *testing.T) {
+	for _, locale := range []string{"zh-CN", "en-US", "de-DE", "ja-JP"} {
+		t.Run(locale, func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join("testdata", "ipconfig_"+locale+".txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := json.MarshalIndent(ParseReport(string(input)), "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			goldenPath := filepath.Join("testdata", "ipconfig_"+locale+".golden.json")
+			if *update {
+				if err := os.WriteFile(goldenPath, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("读取 golden 文件失败（可用 -update 生成）: %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("解析结果与 %s 不一致:\n%s", goldenPath, got)
+			}
+		})
+	}
+}
+
+// TestParseIPv4Config 兼容接口只返回 IPv4 配置（备份还原使用 netsh interface ip）
+func TestParseIPv4Config(t *testing.T) {
+	input, err := os.ReadFile(filepath.Join("testdata", "ipconfig_zh-CN.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configs := Parse(string(input))
+	if len(configs) != 2 {
+		t.Fatalf("期望 2 个适配器，实际 %d", len(configs))
+	}
+
+	wlan := configs[1]
+	if wlan.Name != "WLAN" || !wlan.DHCPEnabled {
+		t.Errorf("适配器信息不符: %+v", wlan)
+	}
+	if len(wlan.Gateways) != 1 || wlan.Gateways[0] != "192.168.1.1" {
+		t.Errorf("网关应只保留 IPv4: %v", wlan.Gateways)
+	}
+	if len(wlan.DNSServers) != 1 || wlan.DNSServers[0] != "192.168.1.1" {
+		t.Errorf("DNS 应只保留 IPv4: %v", wlan.DNSServers)
+	}
+	if len(configs[0].IPAddresses) != 2 || len(configs[0].SubnetMasks) != 2 {
+		t.Errorf("多个 IPv4 地址应一一对应子网掩码: %+v", configs[0])
+	}
+}
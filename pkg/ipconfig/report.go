@@ -0,0 +1,102 @@
+package ipconfig
+
+import (
+	"net/netip"
+
+	"network-rescue-toolkit/pkg/types"
+)
+
+// IPv6 地址类型
+const (
+	ScopeGlobal    = "global"     // 全局单播地址
+	ScopeTemporary = "temporary"  // 临时（隐私扩展）地址
+	ScopeLinkLocal = "link-local" // 本地链接地址 fe80::/10
+	ScopeULA       = "ula"        // 唯一本地地址 fc00::/7
+)
+
+// Report ipconfig /all 的完整解析结果
+type Report struct {
+	HostName            string    `json:"hostName,omitempty"`
+	PrimaryDNSSuffix    string    `json:"primaryDnsSuffix,omitempty"`
+	DNSSuffixSearchList []string  `json:"dnsSuffixSearchList,omitempty"`
+	Adapters            []Adapter `json:"adapters"`
+}
+
+// IPv4Address IPv4 地址及子网
+type IPv4Address struct {
+	Address      string `json:"address"`
+	SubnetMask   string `json:"subnetMask,omitempty"`
+	PrefixLength int    `json:"prefixLength,omitempty"`
+}
+
+// IPv6Address IPv6 地址
+// ipconfig 不输出 IPv6 前缀长度，只有输出中带 "/n" 时 PrefixLength 才非零
+type IPv6Address struct {
+	Address      string `json:"address"`
+	Zone         string `json:"zone,omitempty"`
+	PrefixLength int    `json:"prefixLength,omitempty"`
+	Scope        string `json:"scope"`
+}
+
+// Adapter 单个适配器的配置
+type Adapter struct {
+	Name              string        `json:"name"`
+	Header            string        `json:"header"` // 原始标题行（含适配器类型）
+	Description       string        `json:"description,omitempty"`
+	PhysicalAddress   string        `json:"physicalAddress,omitempty"`
+	MediaDisconnected bool          `json:"mediaDisconnected,omitempty"`
+	DNSSuffix         string        `json:"dnsSuffix,omitempty"`
+	DHCPEnabled       bool          `json:"dhcpEnabled"`
+	AutoconfigEnabled bool          `json:"autoconfigEnabled"`
+	IPv4              []IPv4Address `json:"ipv4,omitempty"`
+	IPv6              []IPv6Address `json:"ipv6,omitempty"`
+	Gateways          []string      `json:"gateways,omitempty"`
+	DHCPServer        string        `json:"dhcpServer,omitempty"`
+	LeaseObtained     string        `json:"leaseObtained,omitempty"` // 按系统区域格式原样保留
+	LeaseExpires      string        `json:"leaseExpires,omitempty"`
+	DHCPv6IAID        string        `json:"dhcpv6Iaid,omitempty"`
+	DHCPv6ClientDUID  string        `json:"dhcpv6ClientDuid,omitempty"`
+	DNSServers        []string      `json:"dnsServers,omitempty"`
+	WINSServers       []string      `json:"winsServers,omitempty"`
+	NetBIOSEnabled    bool          `json:"netbiosEnabled"`
+}
+
+// Config 转换为备份和检查器使用的 IPv4 配置
+func (a Adapter) Config() types.AdapterConfig {
+	config := types.AdapterConfig{
+		Name:        a.Name,
+		DHCPEnabled: a.DHCPEnabled,
+	}
+	for _, addr := range a.IPv4 {
+		config.IPAddresses = append(config.IPAddresses, addr.Address)
+		if addr.SubnetMask != "" {
+			config.SubnetMasks = append(config.SubnetMasks, addr.SubnetMask)
+		}
+	}
+	config.Gateways = filterIPv4(a.Gateways)
+	config.DNSServers = filterIPv4(a.DNSServers)
+	return config
+}
+
+// DefaultGateways 按地址族拆分默认网关
+func (a Adapter) DefaultGateways() (v4, v6 []string) {
+	for _, gw := range a.Gateways {
+		if addr, err := netip.ParseAddr(gw); err == nil && addr.Is6() {
+			v6 = append(v6, gw)
+		} else {
+			v4 = append(v4, gw)
+		}
+	}
+	return v4, v6
+}
+
+// filterIPv4 过滤出 IPv4 地址
+func filterIPv4(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if addr, err := netip.ParseAddr(v); err == nil && addr.Is4() {
+			result = append(result, v)
+		}
+	}
+	return result
+}
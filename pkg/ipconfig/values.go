@@ -0,0 +1,123 @@
+package ipconfig
+
+import (
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	macRegex  = regexp.MustCompile(`^[0-9A-Fa-f]{2}(-[0-9A-Fa-f]{2}){5}(-[0-9A-Fa-f]{2}){0,2}$`)
+	dateYear  = regexp.MustCompile(`(^|\D)(19|20)\d{2}(\D|$)`)
+	dateClock = regexp.MustCompile(`\d{1,2}:\d{2}(:\d{2})?`)
+)
+
+// booleanWords 各语言 ipconfig 输出中表示 是/否、已启用/已禁用 的取值
+// 取值只有这一处依赖显示语言，未识别的取值不会影响其他字段的解析
+var booleanWords = map[string]bool{
+	"yes": true, "no": false,
+	"enabled": true, "disabled": false,
+	"是": true, "否": false,
+	"已启用": true, "已禁用": false,
+	"已啟用": true, "已停用": false,
+	"ja": true, "nein": false,
+	"aktiviert": true, "deaktiviert": false,
+	"はい": true, "いいえ": false,
+	"有効": true, "無効": false,
+	"oui": true, "non": false,
+	"activé": true, "désactivé": false,
+	"sí": true, "si": true,
+	"habilitado": true, "deshabilitado": false,
+}
+
+// parseBool 解析 是/否 类取值
+func parseBool(value string) (bool, bool) {
+	b, ok := booleanWords[strings.ToLower(strings.TrimSpace(value))]
+	return b, ok
+}
+
+// stripAnnotation 去掉地址后的状态说明，如 "(首选)"、"(Preferred)"、"(Bevorzugt)"
+func stripAnnotation(value string) string {
+	if idx := strings.Index(value, "("); idx > 0 {
+		value = value[:idx]
+	}
+	return strings.TrimSpace(value)
+}
+
+// parseIP 解析可能带区域 ID 和前缀长度的地址，如 "fe80::1%12"、"2001:db8::1/64"
+func parseIP(value string) (addr netip.Addr, prefixLength int, ok bool) {
+	value = stripAnnotation(value)
+	if idx := strings.Index(value, "/"); idx > 0 {
+		n, err := strconv.Atoi(value[idx+1:])
+		if err != nil {
+			return netip.Addr{}, 0, false
+		}
+		prefixLength = n
+		value = value[:idx]
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, 0, false
+	}
+	return addr, prefixLength, true
+}
+
+// isIP 判断取值是否为地址
+func isIP(value string) bool {
+	_, _, ok := parseIP(value)
+	return ok
+}
+
+// maskPrefixLength 返回子网掩码对应的前缀长度，不是合法掩码时返回 false
+func maskPrefixLength(value string) (int, bool) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(value))
+	if err != nil || !addr.Is4() {
+		return 0, false
+	}
+	b := addr.As4()
+	bits := uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+	ones := 0
+	for bits&0x80000000 != 0 {
+		ones++
+		bits <<= 1
+	}
+	if bits != 0 {
+		return 0, false
+	}
+	return ones, true
+}
+
+// isMAC 判断取值是否为物理地址（隧道适配器为 8 字节）
+func isMAC(value string) bool {
+	return macRegex.MatchString(strings.TrimSpace(value))
+}
+
+// isDate 判断取值是否为日期时间（租约时间），与区域格式无关
+func isDate(value string) bool {
+	return dateYear.MatchString(value) && dateClock.MatchString(value)
+}
+
+// isDigits 判断取值是否全为数字（DHCPv6 IAID）
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ipv6Scope 根据地址前缀判断 IPv6 地址类型（临时地址由解析器按标签区分）
+func ipv6Scope(addr netip.Addr) string {
+	switch {
+	case addr.IsLinkLocalUnicast():
+		return ScopeLinkLocal
+	case addr.IsPrivate():
+		return ScopeULA
+	}
+	return ScopeGlobal
+}
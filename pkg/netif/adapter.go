@@ -0,0 +1,17 @@
+package netif
+
+// Adapter 系统报告的网卡信息
+type Adapter struct {
+	Index       int
+	Name        string
+	Description string
+	MAC         string
+	Type        uint32   // 接口类型（IANA ifType），未知时为 0
+	Up          bool     // 是否已连接
+	Gateways    []string // IPv4 默认网关
+}
+
+// Kind 返回网卡类型
+func (a Adapter) Kind() Kind {
+	return Classify(a.Name, a.Description, a.MAC, a.Type)
+}
@@ -0,0 +1,72 @@
+//go:build !windows
+
+package netif
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Adapters 通过 net.Interfaces 和 /proc/net/route 读取网卡信息（没有描述和接口类型）
+func Adapters() ([]Adapter, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("获取网卡信息失败: %w", err)
+	}
+	routes := procDefaultRoutes()
+
+	adapters := make([]Adapter, 0, len(interfaces))
+	for _, iface := range interfaces {
+		adapter := Adapter{
+			Index: iface.Index,
+			Name:  iface.Name,
+			MAC:   iface.HardwareAddr.String(),
+			Up:    iface.Flags&net.FlagUp != 0,
+		}
+		for _, route := range routes {
+			if route.Interface == iface.Name {
+				adapter.Gateways = append(adapter.Gateways, route.Gateway)
+			}
+		}
+		adapters = append(adapters, adapter)
+	}
+	return adapters, nil
+}
+
+// procDefaultRoutes 解析 /proc/net/route 中的默认路由，读取失败时返回空
+func procDefaultRoutes() []Route {
+	data, err := os.ReadFile("/proc/net/route")
+	if err != nil {
+		return nil
+	}
+	return parseProcRoutes(string(data))
+}
+
+// parseProcRoutes 解析 /proc/net/route 内容（地址为小端十六进制）
+func parseProcRoutes(content string) []Route {
+	var routes []Route
+	for _, line := range strings.Split(content, "\n")[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 8 || fields[1] != "00000000" || fields[7] != "00000000" {
+			continue
+		}
+		gateway, err := strconv.ParseUint(fields[2], 16, 32)
+		if err != nil || gateway == 0 {
+			continue
+		}
+		metric, _ := strconv.ParseUint(fields[6], 10, 32)
+
+		ip := make(net.IP, 4)
+		binary.LittleEndian.PutUint32(ip, uint32(gateway))
+		routes = append(routes, Route{
+			Interface: fields[0],
+			Gateway:   ip.String(),
+			Metric:    uint32(metric),
+		})
+	}
+	return routes
+}
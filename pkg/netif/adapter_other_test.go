@@ -0,0 +1,23 @@
+//go:build !windows
+
+package netif
+
+import "testing"
+
+func TestParseProcRoutes(t *testing.T) {
+	content := `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+eth0	00000000	0101A8C0	0003	0	0	100	00000000	0	0	0
+eth0	0001A8C0	00000000	0001	0	0	100	00FFFFFF	0	0	0
+wlan0	00000000	0100A8C0	0003	0	0	600	00000000	0	0	0
+`
+	routes := parseProcRoutes(content)
+	if len(routes) != 2 {
+		t.Fatalf("应解析出 2 条默认路由: %+v", routes)
+	}
+	if routes[0].Interface != "eth0" || routes[0].Gateway != "192.168.1.1" || routes[0].Metric != 100 {
+		t.Errorf("默认路由解析错误: %+v", routes[0])
+	}
+	if routes[1].Gateway != "192.168.0.1" || routes[1].Metric != 600 {
+		t.Errorf("默认路由解析错误: %+v", routes[1])
+	}
+}
@@ -0,0 +1,53 @@
+//go:build windows
+
+package netif
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// gaaFlagIncludeGateways GetAdaptersAddresses 返回默认网关
+const gaaFlagIncludeGateways = 0x80
+
+// Adapters 通过 GetAdaptersAddresses 读取网卡的描述、接口类型和默认网关
+func Adapters() ([]Adapter, error) {
+	size := uint32(15000)
+	var buf []byte
+	for {
+		buf = make([]byte, size)
+		err := windows.GetAdaptersAddresses(syscall.AF_INET, gaaFlagIncludeGateways, 0, (*windows.IpAdapterAddresses)(unsafe.Pointer(&buf[0])), &size)
+		if err == nil {
+			break
+		}
+		if err != windows.ERROR_BUFFER_OVERFLOW || size <= uint32(len(buf)) {
+			return nil, fmt.Errorf("获取网卡信息失败: %w", err)
+		}
+	}
+	if size == 0 {
+		return nil, nil
+	}
+
+	var adapters []Adapter
+	for aa := (*windows.IpAdapterAddresses)(unsafe.Pointer(&buf[0])); aa != nil; aa = aa.Next {
+		adapter := Adapter{
+			Index:       int(aa.IfIndex),
+			Name:        windows.UTF16PtrToString(aa.FriendlyName),
+			Description: windows.UTF16PtrToString(aa.Description),
+			MAC:         net.HardwareAddr(aa.PhysicalAddress[:aa.PhysicalAddressLength]).String(),
+			Type:        aa.IfType,
+			Up:          aa.OperStatus == windows.IfOperStatusUp,
+		}
+		for gw := aa.FirstGatewayAddress; gw != nil; gw = gw.Next {
+			if ip := gw.Address.IP(); ip != nil && ip.To4() != nil && !ip.IsUnspecified() {
+				adapter.Gateways = append(adapter.Gateways, ip.String())
+			}
+		}
+		adapters = append(adapters, adapter)
+	}
+	return adapters, nil
+}
@@ -0,0 +1,115 @@
+package netif
+
+import "strings"
+
+// Kind 网卡类型
+type Kind string
+
+const (
+	KindWired    Kind = "wired"    // 物理有线网卡
+	KindWireless Kind = "wireless" // 物理无线网卡
+	KindVirtual  Kind = "virtual"  // 虚拟机、容器等虚拟网卡
+	KindVPN      Kind = "vpn"      // VPN 网卡
+	KindTunnel   Kind = "tunnel"   // IPv6 过渡隧道等系统隧道
+)
+
+// Physical 是否为物理网卡
+func (k Kind) Physical() bool {
+	return k == KindWired || k == KindWireless
+}
+
+// 接口类型（IANA ifType），未知时为 0
+const (
+	ifTypePPP      = 23
+	ifTypeVirtual  = 53
+	ifTypeWireless = 71
+	ifTypeTunnel   = 131
+)
+
+// tunnelKeywords 系统隧道名称或描述中常见的关键字（小写）
+var tunnelKeywords = []string{"teredo", "isatap", "6to4", "ip-https", "tunnel", "隧道"}
+
+// vpnKeywords VPN 网卡名称或描述中常见的关键字（小写）
+var vpnKeywords = []string{
+	"vpn", "tap-windows", "wintun", "wireguard", "openvpn", "anyconnect",
+	"fortinet", "globalprotect", "zerotier", "tailscale", "wan miniport",
+}
+
+// virtualKeywords 虚拟网卡名称或描述中常见的关键字（小写）
+var virtualKeywords = []string{
+	"virtual", "vethernet", "hyper-v", "virtualbox", "vmware", "vmnet",
+	"loopback", "npcap", "docker", "wsl", "虚拟",
+}
+
+// wirelessKeywords 无线网卡名称或描述中常见的关键字（小写）
+var wirelessKeywords = []string{"wi-fi", "wifi", "wlan", "wireless", "802.11", "无线"}
+
+// virtualOUIs 虚拟化软件使用的 MAC 地址前缀（大写、冒号分隔）
+var virtualOUIs = []string{
+	"00:15:5D", // Hyper-V
+	"08:00:27", // VirtualBox
+	"0A:00:27", // VirtualBox 仅主机网络
+	"00:05:69", // VMware
+	"00:0C:29", // VMware
+	"00:1C:14", // VMware
+	"00:50:56", // VMware
+	"00:1C:42", // Parallels
+	"02:42:",   // Docker
+}
+
+// vpnOUIs VPN 软件使用的 MAC 地址前缀
+var vpnOUIs = []string{
+	"00:FF:", // TAP-Windows 默认地址
+}
+
+// Classify 根据名称、描述、MAC 地址和接口类型判断网卡类型
+// 接口类型明确为隧道或拨号时优先使用；其次依次匹配 VPN、隧道、虚拟网卡的关键字和 MAC 前缀
+func Classify(name, description, mac string, ifType uint32) Kind {
+	text := strings.ToLower(name + " " + description)
+	mac = normalizeMAC(mac)
+
+	switch {
+	case ifType == ifTypeTunnel:
+		return KindTunnel
+	case ifType == ifTypePPP || containsAny(text, vpnKeywords) || hasAnyPrefix(mac, vpnOUIs):
+		return KindVPN
+	case containsAny(text, tunnelKeywords):
+		return KindTunnel
+	case ifType == ifTypeVirtual || containsAny(text, virtualKeywords) || hasAnyPrefix(mac, virtualOUIs):
+		return KindVirtual
+	case ifType == ifTypeWireless || containsAny(text, wirelessKeywords):
+		return KindWireless
+	default:
+		return KindWired
+	}
+}
+
+// IsVirtual 根据名称、描述和 MAC 地址判断是否为虚拟网卡（含 VPN 和隧道）
+func IsVirtual(name, description, mac string) bool {
+	return !Classify(name, description, mac, 0).Physical()
+}
+
+// containsAny 文本是否包含任一关键字
+func containsAny(text string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAnyPrefix 文本是否以任一前缀开头
+func hasAnyPrefix(text string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeMAC 统一为大写、冒号分隔的 MAC 地址
+func normalizeMAC(mac string) string {
+	return strings.ToUpper(strings.ReplaceAll(mac, "-", ":"))
+}
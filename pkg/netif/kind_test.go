@@ -0,0 +1,31 @@
+package netif
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name, description, mac string
+		ifType                 uint32
+		want                   Kind
+	}{
+		{"以太网", "Intel(R) Ethernet Connection I219-V", "3c:52:82:11:22:33", 6, KindWired},
+		{"WLAN", "Intel(R) Wi-Fi 6 AX201 160MHz", "a4:c3:f0:11:22:33", 71, KindWireless},
+		{"无线网络连接", "", "a4:c3:f0:11:22:33", 0, KindWireless},
+		{"vEthernet (Default Switch)", "Hyper-V Virtual Ethernet Adapter", "00:15:5d:01:02:03", 6, KindVirtual},
+		{"以太网 3", "VirtualBox Host-Only Ethernet Adapter", "0a:00:27:00:00:03", 6, KindVirtual},
+		{"以太网 4", "", "00-15-5D-01-02-03", 0, KindVirtual},
+		{"本地连接", "TAP-Windows Adapter V9", "00:ff:12:34:56:78", 6, KindVPN},
+		{"公司网络", "", "", 23, KindVPN},
+		{"Teredo Tunneling Pseudo-Interface", "", "", 0, KindTunnel},
+		{"以太网 5", "", "", 131, KindTunnel},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.name, tt.description, tt.mac, tt.ifType); got != tt.want {
+			t.Errorf("Classify(%q, %q, %q, %d) = %s，期望 %s", tt.name, tt.description, tt.mac, tt.ifType, got, tt.want)
+		}
+	}
+
+	if !IsVirtual("本地连接", "TAP-Windows Adapter V9", "") || IsVirtual("WLAN", "", "") {
+		t.Error("VPN 网卡应视为虚拟网卡，无线网卡不应视为虚拟网卡")
+	}
+}
@@ -0,0 +1,73 @@
+package netif
+
+import (
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// IPv4MappedPrefix IPv4 映射地址的前缀，其优先级决定系统优先使用 IPv4 还是 IPv6
+const IPv4MappedPrefix = "::ffff:0:0/96"
+
+// ipv6DefaultPrefix 原生 IPv6 地址匹配的前缀
+const ipv6DefaultPrefix = "::/0"
+
+// PrefixPolicy IPv6 前缀策略表中的一项
+type PrefixPolicy struct {
+	Prefix     string `json:"prefix"`
+	Precedence int    `json:"precedence"` // 优先级，越大越优先
+	Label      int    `json:"label"`
+}
+
+// ParsePrefixPolicies 解析 netsh interface ipv6 show prefixpolicies 的输出
+// 每行为"优先级 标签 前缀"三列，与系统语言无关；表头和其它行被忽略
+func ParsePrefixPolicies(output string) []PrefixPolicy {
+	var policies []PrefixPolicy
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		precedence, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		label, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(fields[2]); err != nil || !prefix.Addr().Is6() {
+			continue
+		}
+		policies = append(policies, PrefixPolicy{
+			Prefix:     fields[2],
+			Precedence: precedence,
+			Label:      label,
+		})
+	}
+	return policies
+}
+
+// FindPrefixPolicy 按前缀查找策略（比较解析后的前缀，不受书写形式影响）
+func FindPrefixPolicy(policies []PrefixPolicy, prefix string) (PrefixPolicy, bool) {
+	want, err := netip.ParsePrefix(prefix)
+	if err != nil {
+		return PrefixPolicy{}, false
+	}
+	for _, policy := range policies {
+		if got, err := netip.ParsePrefix(policy.Prefix); err == nil && got == want {
+			return policy, true
+		}
+	}
+	return PrefixPolicy{}, false
+}
+
+// PrefersIPv4 IPv4 映射地址的优先级是否高于原生 IPv6 地址
+func PrefersIPv4(policies []PrefixPolicy) bool {
+	mapped, ok := FindPrefixPolicy(policies, IPv4MappedPrefix)
+	if !ok {
+		return false
+	}
+	native, ok := FindPrefixPolicy(policies, ipv6DefaultPrefix)
+	return !ok || mapped.Precedence > native.Precedence
+}
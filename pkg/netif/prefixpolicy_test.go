@@ -0,0 +1,48 @@
+package netif
+
+import "testing"
+
+// samplePrefixPolicies Windows 默认的前缀策略表
+const samplePrefixPolicies = `
+查询活动状态...
+
+优先顺序  标签  前缀
+----------  -----  --------------------------------
+        50      0  ::1/128
+        40      1  ::/0
+        35      4  ::ffff:0:0/96
+        30      2  2002::/16
+         5      5  2001::/32
+         3     13  fc00::/7
+         1     11  fec0::/10
+         1     12  3ffe::/16
+         1      3  ::/96
+`
+
+func TestParsePrefixPolicies(t *testing.T) {
+	policies := ParsePrefixPolicies(samplePrefixPolicies)
+	if len(policies) != 9 {
+		t.Fatalf("应解析出 9 条前缀策略: %+v", policies)
+	}
+
+	mapped, ok := FindPrefixPolicy(policies, IPv4MappedPrefix)
+	if !ok || mapped.Precedence != 35 || mapped.Label != 4 {
+		t.Errorf("IPv4 映射前缀策略不符: %+v", mapped)
+	}
+	if PrefersIPv4(policies) {
+		t.Error("默认策略下应优先使用 IPv6")
+	}
+}
+
+func TestPrefersIPv4(t *testing.T) {
+	policies := []PrefixPolicy{
+		{Prefix: "::/0", Precedence: 40, Label: 1},
+		{Prefix: "::ffff:0:0/96", Precedence: 100, Label: 4},
+	}
+	if !PrefersIPv4(policies) {
+		t.Error("IPv4 映射前缀优先级更高时应优先使用 IPv4")
+	}
+	if PrefersIPv4(policies[:1]) {
+		t.Error("没有 IPv4 映射前缀策略时不应认为优先使用 IPv4")
+	}
+}
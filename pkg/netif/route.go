@@ -0,0 +1,45 @@
+package netif
+
+import (
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Route IPv4 默认路由
+type Route struct {
+	Interface     string `json:"interface,omitempty"`
+	InterfaceAddr string `json:"interfaceAddr,omitempty"` // 出口网卡的 IPv4 地址
+	Gateway       string `json:"gateway"`
+	Metric        uint32 `json:"metric"` // 有效跃点数（路由跃点数 + 接口跃点数），越小越优先
+}
+
+// ParseRoutePrint 解析 route print -4 的活动路由，返回默认路由（按跃点数从小到大排列）
+// 活动路由每行为"目标 掩码 网关 接口 跃点数"五列，与系统语言无关；永久路由只有四列，网关为"在链路上"的路由没有下一跳，均被忽略
+func ParseRoutePrint(output string) []Route {
+	var routes []Route
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 5 || fields[0] != "0.0.0.0" || fields[1] != "0.0.0.0" {
+			continue
+		}
+		gateway, err := netip.ParseAddr(fields[2])
+		if err != nil || !gateway.Is4() {
+			continue
+		}
+		metric, err := strconv.ParseUint(fields[4], 10, 32)
+		if err != nil {
+			continue
+		}
+		routes = append(routes, Route{
+			InterfaceAddr: fields[3],
+			Gateway:       gateway.String(),
+			Metric:        uint32(metric),
+		})
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].Metric < routes[j].Metric
+	})
+	return routes
+}
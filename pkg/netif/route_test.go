@@ -0,0 +1,36 @@
+package netif
+
+import "testing"
+
+func TestParseRoutePrint(t *testing.T) {
+	output := `
+===========================================================================
+接口列表
+  4...3c 52 82 11 22 33 ......Realtek PCIe GbE Family Controller
+  7...a4 c3 f0 11 22 33 ......Intel(R) Wi-Fi 6 AX201 160MHz
+===========================================================================
+
+IPv4 路由表
+===========================================================================
+活动路由:
+网络目标        网络掩码          网关       接口   跃点数
+          0.0.0.0          0.0.0.0      192.168.0.1    192.168.0.20     35
+          0.0.0.0          0.0.0.0      192.168.1.1    192.168.1.20     25
+          0.0.0.0          0.0.0.0            在链路上       10.8.0.2      5
+===========================================================================
+永久路由:
+  网络地址          网络掩码  网关地址  跃点数
+          0.0.0.0          0.0.0.0      192.168.1.1  默认
+===========================================================================
+`
+	routes := ParseRoutePrint(output)
+	if len(routes) != 2 {
+		t.Fatalf("应解析出 2 条有下一跳的活动默认路由: %+v", routes)
+	}
+	if routes[0].Gateway != "192.168.1.1" || routes[0].InterfaceAddr != "192.168.1.20" || routes[0].Metric != 25 {
+		t.Errorf("跃点数最小的默认路由应排在最前: %+v", routes[0])
+	}
+	if routes[1].Gateway != "192.168.0.1" {
+		t.Errorf("默认路由解析错误: %+v", routes[1])
+	}
+}
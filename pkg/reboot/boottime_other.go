@@ -0,0 +1,27 @@
+//go:build !windows
+
+package reboot
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BootTime 返回系统本次启动的时间（读取 /proc/uptime，失败时返回零值）
+func BootTime() time.Time {
+	data, err := os.ReadFile("/proc/uptime")
+	if err != nil {
+		return time.Time{}
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return time.Time{}
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Now().Add(-time.Duration(seconds * float64(time.Second)))
+}
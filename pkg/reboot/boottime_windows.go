@@ -0,0 +1,20 @@
+//go:build windows
+
+package reboot
+
+import (
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	kernel32           = windows.NewLazySystemDLL("kernel32.dll")
+	procGetTickCount64 = kernel32.NewProc("GetTickCount64")
+)
+
+// BootTime 返回系统本次启动的时间
+func BootTime() time.Time {
+	uptime, _, _ := procGetTickCount64.Call()
+	return time.Now().Add(-time.Duration(uptime) * time.Millisecond)
+}
@@ -0,0 +1,47 @@
+package reboot
+
+import (
+	"errors"
+	"fmt"
+
+	"network-rescue-toolkit/pkg/registry"
+)
+
+// RunOncePath 当前用户下次登录时执行一次的启动项
+const RunOncePath = `Software\Microsoft\Windows\CurrentVersion\RunOnce`
+
+// runOnceName 启动项名称
+const runOnceName = "NetworkRescueVerify"
+
+// LoginHook 下次登录时自动启动程序，用于重启后验证修复
+type LoginHook struct {
+	store registry.Store
+}
+
+// NewLoginHook 创建登录启动项
+func NewLoginHook(store registry.Store) *LoginHook {
+	return &LoginHook{store: store}
+}
+
+// Schedule 下次登录时启动指定程序（RunOnce 执行后系统会自动删除该项）
+func (h *LoginHook) Schedule(exe string) error {
+	if err := h.store.WriteString(RunOncePath, runOnceName, `"`+exe+`"`); err != nil {
+		return fmt.Errorf("设置登录启动项失败: %w", err)
+	}
+	return nil
+}
+
+// Cancel 取消尚未执行的登录启动项
+func (h *LoginHook) Cancel() error {
+	err := h.store.DeleteValue(RunOncePath, runOnceName)
+	if err != nil && !errors.Is(err, registry.ErrValueNotFound) {
+		return fmt.Errorf("删除登录启动项失败: %w", err)
+	}
+	return nil
+}
+
+// Scheduled 是否已设置登录启动项
+func (h *LoginHook) Scheduled() bool {
+	_, err := h.store.ReadString(RunOncePath, runOnceName)
+	return err == nil
+}
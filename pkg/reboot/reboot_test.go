@@ -0,0 +1,166 @@
+package reboot
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"network-rescue-toolkit/pkg/registry"
+	"network-rescue-toolkit/pkg/types"
+)
+
+// result 构造指定状态的诊断结果
+func result(id string, status types.DiagnosticStatus) types.DiagnosticResult {
+	r := types.NewDiagnosticResult(id, id+"-检查")
+	r.Status = status
+	r.Repairable = status != types.StatusOK
+	return *r
+}
+
+// winsockRepair 构造需要重启的 Winsock 修复结果
+func winsockRepair(before ...types.DiagnosticResult) types.RepairResult {
+	r := types.NewRepairResult("winsock", "重置 Winsock")
+	r.SetSuccess("Winsock 重置成功")
+	r.SetRequireReboot()
+	r.Verification = &types.Verification{Status: types.VerificationPendingReboot, Before: before}
+	return *r
+}
+
+func TestNewRecordOnlyKeepsRebootRepairs(t *testing.T) {
+	dns := types.NewRepairResult("dns", "刷新 DNS 缓存")
+	dns.SetSuccess("DNS 缓存已刷新")
+
+	if NewRecord([]types.RepairResult{*dns}, nil) != nil {
+		t.Error("没有需要重启的修复时不应创建记录")
+	}
+	record := NewRecord([]types.RepairResult{*dns, winsockRepair()}, nil)
+	if record == nil || len(record.Repairs) != 1 || record.Repairs[0].ID != "winsock" {
+		t.Errorf("应只记录需要重启的修复: %+v", record)
+	}
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "pending.json"))
+	if record, err := store.Load(); record != nil || err != nil {
+		t.Fatalf("没有记录时应返回 nil: %+v %v", record, err)
+	}
+
+	before := []types.DiagnosticResult{result("dns", types.StatusError)}
+	if err := store.Save(*NewRecord([]types.RepairResult{winsockRepair()}, before)); err != nil {
+		t.Fatal(err)
+	}
+	tcpip := types.NewRepairResult("tcpip", "重置 TCP/IP")
+	tcpip.SetSuccess("ok")
+	tcpip.SetRequireReboot()
+	if err := store.Save(*NewRecord([]types.RepairResult{*tcpip}, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := store.Load()
+	if err != nil || len(record.Repairs) != 2 || len(record.Before) != 1 {
+		t.Fatalf("再次保存应合并修复项并保留重启前的诊断结果: %+v %v", record, err)
+	}
+
+	store.Clear()
+	if record, _ := store.Load(); record != nil {
+		t.Error("清除后不应再有记录")
+	}
+}
+
+func TestRecordDue(t *testing.T) {
+	record := Record{CreatedAt: time.Now()}
+	if record.Due(time.Now().Add(-time.Hour)) {
+		t.Error("记录之后未重启时不应验证")
+	}
+	if !record.Due(time.Now().Add(time.Minute)) {
+		t.Error("记录之后重启过应验证")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	record := Record{
+		CreatedAt: time.Now(),
+		Repairs:   []types.RepairResult{winsockRepair(result("dns", types.StatusError), result("connectivity", types.StatusError))},
+		Before: []types.DiagnosticResult{
+			result("adapter", types.StatusOK),
+			result("dns", types.StatusError),
+			result("connectivity", types.StatusError),
+		},
+	}
+
+	v := Compare(record, []types.DiagnosticResult{
+		result("adapter", types.StatusOK),
+		result("dns", types.StatusOK),
+		result("connectivity", types.StatusOK),
+	})
+	if !v.Worked || len(v.Repairs) != 1 || !v.Repairs[0].Worked || len(v.Repairs[0].Checks) != 2 {
+		t.Errorf("重启后恢复正常时应判断为生效: %+v", v)
+	}
+	if v.Changes[1].Outcome != OutcomeFixed || v.Changes[0].Outcome != OutcomeUnchanged {
+		t.Errorf("诊断项变化不符: %+v", v.Changes)
+	}
+
+	v = Compare(record, []types.DiagnosticResult{
+		result("adapter", types.StatusError),
+		result("dns", types.StatusOK),
+		result("connectivity", types.StatusError),
+	})
+	if v.Worked || v.Repairs[0].Worked {
+		t.Errorf("仍有诊断项未通过时应判断为未生效: %+v", v)
+	}
+	if v.Changes[0].Outcome != OutcomeRegressed || v.Changes[2].Outcome != OutcomeStillFailing {
+		t.Errorf("诊断项变化不符: %+v", v.Changes)
+	}
+	if v.Message != "重启后重新诊断，connectivity-检查 仍未通过，重置未能解决问题" {
+		t.Errorf("应只列出修复验证项中未通过的项目: %s", v.Message)
+	}
+}
+
+func TestCompareIgnoresUnrelatedFailures(t *testing.T) {
+	record := Record{
+		CreatedAt: time.Now(),
+		Repairs:   []types.RepairResult{winsockRepair(result("dns", types.StatusError), result("connectivity", types.StatusError))},
+		Before: []types.DiagnosticResult{
+			result("dns", types.StatusError),
+			result("connectivity", types.StatusError),
+			result("proxy", types.StatusWarning),
+		},
+	}
+
+	v := Compare(record, []types.DiagnosticResult{
+		result("dns", types.StatusOK),
+		result("connectivity", types.StatusOK),
+		result("proxy", types.StatusWarning),
+		result("ipv6", types.StatusWarning),
+	})
+	if !v.Worked || !v.Repairs[0].Worked {
+		t.Errorf("修复的验证项都已通过时应判断为生效: %+v", v)
+	}
+	if len(v.Unrelated) != 2 || v.Unrelated[0].ID != "proxy" || v.Unrelated[1].ID != "ipv6" {
+		t.Errorf("应单独列出与重置无关的未通过项: %+v", v.Unrelated)
+	}
+	if v.Changes[3].Outcome != OutcomeNewFailure {
+		t.Errorf("重启前没有结果、重启后未通过的项应标记为新问题: %+v", v.Changes[3])
+	}
+	if v.Message != "重启后重新诊断，重置已生效；proxy-检查、ipv6-检查 未通过，与本次重置无关" {
+		t.Errorf("结论不符: %s", v.Message)
+	}
+}
+
+func TestLoginHook(t *testing.T) {
+	store := registry.NewMemoryStore()
+	hook := NewLoginHook(store)
+
+	if err := hook.Schedule(`C:\Tools\NetworkRescue.exe`); err != nil || !hook.Scheduled() {
+		t.Fatalf("设置登录启动项失败: %v", err)
+	}
+	if value, _ := store.ReadString(RunOncePath, runOnceName); value != `"C:\Tools\NetworkRescue.exe"` {
+		t.Errorf("启动项应为带引号的程序路径: %s", value)
+	}
+	if err := hook.Cancel(); err != nil || hook.Scheduled() {
+		t.Errorf("取消登录启动项失败: %v", err)
+	}
+	if err := hook.Cancel(); err != nil {
+		t.Errorf("重复取消不应报错: %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package reboot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"network-rescue-toolkit/pkg/types"
+)
+
+// Record 等待重启后验证的修复记录
+type Record struct {
+	CreatedAt time.Time                `json:"createdAt"`
+	Repairs   []types.RepairResult     `json:"repairs"` // 需要重启才能生效的修复
+	Before    []types.DiagnosticResult `json:"before"`  // 重启前最近一次完整诊断结果
+}
+
+// Due 系统是否已在记录创建后重启过
+func (r *Record) Due(bootTime time.Time) bool {
+	return bootTime.After(r.CreatedAt)
+}
+
+// NewRecord 从修复结果中挑出需要重启的修复，没有时返回 nil
+func NewRecord(repairs []types.RepairResult, before []types.DiagnosticResult) *Record {
+	record := &Record{
+		CreatedAt: time.Now(),
+		Before:    before,
+	}
+	for _, r := range repairs {
+		if r.Success && r.RequireReboot {
+			record.Repairs = append(record.Repairs, r)
+		}
+	}
+	if len(record.Repairs) == 0 {
+		return nil
+	}
+	return record
+}
+
+// Store 持久化保存待验证记录，程序退出、系统重启后仍然保留
+type Store struct {
+	path string
+}
+
+// NewStore 使用指定文件创建记录存储
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// NewDefaultStore 创建默认记录存储（~/.network-rescue-toolkit/pending-verification.json）
+func NewDefaultStore() *Store {
+	homeDir, _ := os.UserHomeDir()
+	return NewStore(filepath.Join(homeDir, ".network-rescue-toolkit", "pending-verification.json"))
+}
+
+// Path 返回记录文件路径
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Save 保存待验证记录；已有记录时合并修复项，保留最早的重启前诊断结果
+func (s *Store) Save(record Record) error {
+	existing, err := s.Load()
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		existing.Repairs = append(existing.Repairs, record.Repairs...)
+		existing.CreatedAt = record.CreatedAt
+		if len(existing.Before) == 0 {
+			existing.Before = record.Before
+		}
+		record = *existing
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化待验证记录失败: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("保存待验证记录失败: %w", err)
+	}
+	return nil
+}
+
+// Load 读取待验证记录，没有记录时返回 nil
+func (s *Store) Load() (*Record, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取待验证记录失败: %w", err)
+	}
+
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("待验证记录已损坏: %w", err)
+	}
+	return &record, nil
+}
+
+// Clear 删除待验证记录
+func (s *Store) Clear() error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("删除待验证记录失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package reboot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"network-rescue-toolkit/pkg/types"
+)
+
+// Outcome 单个诊断项在重启前后的变化
+type Outcome string
+
+const (
+	OutcomeFixed        Outcome = "fixed"         // 重启前未通过，重启后通过
+	OutcomeStillFailing Outcome = "still-failing" // 重启前后均未通过
+	OutcomeRegressed    Outcome = "regressed"     // 重启前通过，重启后未通过
+	OutcomeUnchanged    Outcome = "unchanged"     // 重启前后均通过
+	OutcomeNewFailure   Outcome = "new-failure"   // 重启前没有该项结果，重启后未通过
+)
+
+// CheckChange 诊断项在重启前后的状态
+type CheckChange struct {
+	ID      string                 `json:"id"`
+	Name    string                 `json:"name"`
+	Before  types.DiagnosticStatus `json:"before,omitempty"` // 重启前没有该项结果时为空
+	After   types.DiagnosticStatus `json:"after"`
+	Message string                 `json:"message"` // 重启后的诊断结论
+	Outcome Outcome                `json:"outcome"`
+}
+
+// RepairOutcome 单个需要重启的修复是否生效
+type RepairOutcome struct {
+	RepairerID string        `json:"repairerId"`
+	Name       string        `json:"name"`
+	Worked     bool          `json:"worked"`
+	Checks     []CheckChange `json:"checks"` // 修复时声明的验证诊断项
+}
+
+// Verification 重启后的验证结论
+type Verification struct {
+	RecordedAt time.Time       `json:"recordedAt"`
+	VerifiedAt time.Time       `json:"verifiedAt"`
+	Repairs    []RepairOutcome `json:"repairs"`
+	Changes    []CheckChange   `json:"changes"`
+	Unrelated  []CheckChange   `json:"unrelated"` // 重启后未通过、但不属于任何修复验证项的诊断项
+	Worked     bool            `json:"worked"`    // 所有修复的验证项都已通过`
+	Message    string          `json:"message"`
+}
+
+// Compare 将重启后的诊断结果与重启前保存的结果比较
+func Compare(record Record, after []types.DiagnosticResult) Verification {
+	v := Verification{
+		RecordedAt: record.CreatedAt,
+		VerifiedAt: time.Now(),
+		Repairs:    make([]RepairOutcome, 0, len(record.Repairs)),
+		Changes:    make([]CheckChange, 0, len(after)),
+		Unrelated:  make([]CheckChange, 0),
+		Worked:     true,
+	}
+
+	before := make(map[string]types.DiagnosticResult, len(record.Before))
+	for _, r := range record.Before {
+		before[r.ID] = r
+	}
+	afterByID := make(map[string]types.DiagnosticResult, len(after))
+	for _, r := range after {
+		afterByID[r.ID] = r
+		v.Changes = append(v.Changes, compareCheck(before, r))
+	}
+
+	// 是否生效只看修复自己的验证项，代理、IPv6 等与重置无关的问题单独列出
+	verified := make(map[string]bool)
+	var failing []string
+	for _, repair := range record.Repairs {
+		outcome := RepairOutcome{RepairerID: repair.ID, Name: repair.Name, Worked: true, Checks: make([]CheckChange, 0)}
+		if repair.Verification != nil {
+			// 修复时记录的验证项比完整诊断更早，用它作为该修复的重启前状态
+			repairBefore := make(map[string]types.DiagnosticResult, len(repair.Verification.Before))
+			for _, r := range repair.Verification.Before {
+				repairBefore[r.ID] = r
+			}
+			for _, prev := range repair.Verification.Before {
+				r, ok := afterByID[prev.ID]
+				if !ok {
+					continue
+				}
+				change := compareCheck(repairBefore, r)
+				outcome.Checks = append(outcome.Checks, change)
+				if r.NeedsRepair() {
+					outcome.Worked = false
+					if !verified[r.ID] {
+						failing = append(failing, r.Name)
+					}
+				}
+				verified[r.ID] = true
+			}
+		}
+		if !outcome.Worked {
+			v.Worked = false
+		}
+		v.Repairs = append(v.Repairs, outcome)
+	}
+
+	var unrelated []string
+	for _, change := range v.Changes {
+		r := afterByID[change.ID]
+		if !verified[change.ID] && r.NeedsRepair() {
+			v.Unrelated = append(v.Unrelated, change)
+			unrelated = append(unrelated, change.Name)
+		}
+	}
+
+	switch {
+	case !v.Worked:
+		v.Message = fmt.Sprintf("重启后重新诊断，%s 仍未通过，重置未能解决问题", strings.Join(failing, "、"))
+	case len(unrelated) > 0:
+		v.Message = fmt.Sprintf("重启后重新诊断，重置已生效；%s 未通过，与本次重置无关", strings.Join(unrelated, "、"))
+	default:
+		v.Message = "重启后重新诊断，网络已恢复正常，重置已生效"
+	}
+	return v
+}
+
+// compareCheck 比较单个诊断项
+func compareCheck(before map[string]types.DiagnosticResult, after types.DiagnosticResult) CheckChange {
+	change := CheckChange{
+		ID:      after.ID,
+		Name:    after.Name,
+		After:   after.Status,
+		Message: after.Message,
+	}
+
+	prev, known := before[after.ID]
+	failedBefore := known && prev.NeedsRepair()
+	if known {
+		change.Before = prev.Status
+	}
+
+	switch {
+	case after.NeedsRepair() && !known:
+		change.Outcome = OutcomeNewFailure
+	case after.NeedsRepair() && failedBefore:
+		change.Outcome = OutcomeStillFailing
+	case after.NeedsRepair():
+		change.Outcome = OutcomeRegressed
+	case failedBefore:
+		change.Outcome = OutcomeFixed
+	default:
+		change.Outcome = OutcomeUnchanged
+	}
+	return change
+}
@@ -1,3 +1,5 @@
+//go:build windows
+
 package registry
 
 import (
@@ -116,9 +118,3 @@ func (h *RegistryHelper) ValueExists(root registry.Key, path, name string) bool
 	_, _, err = key.GetStringValue(name)
 	return err == nil
 }
-
-// 常用注册表路径
-const (
-	// ProxySettingsPath IE 代理设置路径
-	ProxySettingsPath = `Software\Microsoft\Windows\CurrentVersion\Internet Settings`
-)
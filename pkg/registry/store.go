@@ -0,0 +1,114 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// 常用注册表路径
+const (
+	// ProxySettingsPath IE 代理设置路径
+	ProxySettingsPath = `Software\Microsoft\Windows\CurrentVersion\Internet Settings`
+)
+
+// ErrValueNotFound 注册表值不存在
+var ErrValueNotFound = errors.New("注册表值不存在")
+
+// Store 注册表读写抽象（固定在 HKEY_CURRENT_USER 下）
+// 业务代码只依赖该接口，便于在非 Windows 平台用内存实现进行测试
+type Store interface {
+	// ReadString 读取字符串值
+	ReadString(path, name string) (string, error)
+	// ReadDWORD 读取 DWORD 值
+	ReadDWORD(path, name string) (uint32, error)
+	// WriteString 写入字符串值
+	WriteString(path, name, value string) error
+	// WriteDWORD 写入 DWORD 值
+	WriteDWORD(path, name string, value uint32) error
+	// DeleteValue 删除值（值不存在时返回 ErrValueNotFound）
+	DeleteValue(path, name string) error
+}
+
+// MemoryStore 基于内存的注册表实现（用于测试和离线分析）
+type MemoryStore struct {
+	strings map[string]string
+	dwords  map[string]uint32
+	mu      sync.RWMutex
+}
+
+// NewMemoryStore 创建内存注册表
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		strings: make(map[string]string),
+		dwords:  make(map[string]uint32),
+	}
+}
+
+// SetString 预置字符串值
+func (s *MemoryStore) SetString(path, name, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.strings[memoryKey(path, name)] = value
+}
+
+// SetDWORD 预置 DWORD 值
+func (s *MemoryStore) SetDWORD(path, name string, value uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dwords[memoryKey(path, name)] = value
+}
+
+// ReadString 读取字符串值
+func (s *MemoryStore) ReadString(path, name string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.strings[memoryKey(path, name)]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrValueNotFound, name)
+	}
+	return value, nil
+}
+
+// ReadDWORD 读取 DWORD 值
+func (s *MemoryStore) ReadDWORD(path, name string) (uint32, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.dwords[memoryKey(path, name)]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrValueNotFound, name)
+	}
+	return value, nil
+}
+
+// WriteString 写入字符串值
+func (s *MemoryStore) WriteString(path, name, value string) error {
+	s.SetString(path, name, value)
+	return nil
+}
+
+// WriteDWORD 写入 DWORD 值
+func (s *MemoryStore) WriteDWORD(path, name string, value uint32) error {
+	s.SetDWORD(path, name, value)
+	return nil
+}
+
+// DeleteValue 删除值
+func (s *MemoryStore) DeleteValue(path, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := memoryKey(path, name)
+	_, hasString := s.strings[key]
+	_, hasDWORD := s.dwords[key]
+	if !hasString && !hasDWORD {
+		return fmt.Errorf("%w: %s", ErrValueNotFound, name)
+	}
+	delete(s.strings, key)
+	delete(s.dwords, key)
+	return nil
+}
+
+// memoryKey 生成内存存储键
+func memoryKey(path, name string) string {
+	return path + `\` + name
+}
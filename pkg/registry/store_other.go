@@ -0,0 +1,8 @@
+//go:build !windows
+
+package registry
+
+// NewDefaultStore 非 Windows 平台没有系统注册表，返回空的内存注册表
+func NewDefaultStore() Store {
+	return NewMemoryStore()
+}
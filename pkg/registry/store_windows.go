@@ -0,0 +1,62 @@
+//go:build windows
+
+package registry
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// CurrentUserStore 基于 HKEY_CURRENT_USER 的注册表实现
+type CurrentUserStore struct {
+	helper *RegistryHelper
+}
+
+// NewCurrentUserStore 创建 HKCU 注册表存储
+func NewCurrentUserStore() *CurrentUserStore {
+	return &CurrentUserStore{
+		helper: NewRegistryHelper(),
+	}
+}
+
+// ReadString 读取字符串值
+func (s *CurrentUserStore) ReadString(path, name string) (string, error) {
+	value, err := s.helper.ReadString(registry.CURRENT_USER, path, name)
+	return value, translateError(name, err)
+}
+
+// ReadDWORD 读取 DWORD 值
+func (s *CurrentUserStore) ReadDWORD(path, name string) (uint32, error) {
+	value, err := s.helper.ReadDWORD(registry.CURRENT_USER, path, name)
+	return value, translateError(name, err)
+}
+
+// WriteString 写入字符串值
+func (s *CurrentUserStore) WriteString(path, name, value string) error {
+	return s.helper.WriteString(registry.CURRENT_USER, path, name, value)
+}
+
+// WriteDWORD 写入 DWORD 值
+func (s *CurrentUserStore) WriteDWORD(path, name string, value uint32) error {
+	return s.helper.WriteDWORD(registry.CURRENT_USER, path, name, value)
+}
+
+// DeleteValue 删除值
+func (s *CurrentUserStore) DeleteValue(path, name string) error {
+	return translateError(name, s.helper.DeleteValue(registry.CURRENT_USER, path, name))
+}
+
+// translateError 将"值不存在"统一转换为 ErrValueNotFound
+func translateError(name string, err error) error {
+	if err != nil && errors.Is(err, registry.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrValueNotFound, name)
+	}
+	return err
+}
+
+// NewDefaultStore 返回当前平台默认的注册表存储
+func NewDefaultStore() Store {
+	return NewCurrentUserStore()
+}
@@ -118,6 +118,7 @@ const htmlTemplate = `<!DOCTYPE html>
         .result-ok { background: #f0fdf4; border-color: #22c55e; }
         .result-warning { background: #fffbeb; border-color: #f97316; }
         .result-error { background: #fef2f2; border-color: #ef4444; }
+        .result-skipped { background: #f8fafc; border-color: #94a3b8; }
         .result h3 { margin: 0 0 5px 0; }
         .result p { margin: 5px 0; color: #666; }
         .info { color: #666; font-size: 14px; margin-top: 20px; }
@@ -0,0 +1,34 @@
+package types
+
+// Confidence 根因判断的可信程度
+type Confidence string
+
+const (
+	ConfidenceHigh   Confidence = "high"
+	ConfidenceMedium Confidence = "medium"
+	ConfidenceLow    Confidence = "low"
+)
+
+// ConfidenceFromScore 根据评分（0~1）得出可信程度
+func ConfidenceFromScore(score float64) Confidence {
+	switch {
+	case score >= 0.8:
+		return ConfidenceHigh
+	case score >= 0.5:
+		return ConfidenceMedium
+	default:
+		return ConfidenceLow
+	}
+}
+
+// RootCause 根据诊断结果推断出的故障根因
+type RootCause struct {
+	ID            string     `json:"id"`
+	Title         string     `json:"title"`
+	Description   string     `json:"description"`
+	Score         float64    `json:"score"` // 0~1，用于排序
+	Confidence    Confidence `json:"confidence"`
+	Evidence      []string   `json:"evidence"`      // 支持该判断的诊断结果
+	RelatedChecks []string   `json:"relatedChecks"` // 由该根因导致的诊断项
+	RepairerID    string     `json:"repairerId"`    // 建议优先执行的修复器
+}
@@ -9,6 +9,7 @@ const (
 	StatusOK      DiagnosticStatus = "ok"
 	StatusWarning DiagnosticStatus = "warning"
 	StatusError   DiagnosticStatus = "error"
+	StatusSkipped DiagnosticStatus = "skipped" // 依赖的检查项失败，未执行
 )
 
 // DiagnosticResult 诊断结果
@@ -54,6 +55,13 @@ func (r *DiagnosticResult) SetError(message string, repairable bool) {
 	r.Repairable = repairable
 }
 
+// SetSkipped 设置为跳过状态
+func (r *DiagnosticResult) SetSkipped(message string) {
+	r.Status = StatusSkipped
+	r.Message = message
+	r.Repairable = false
+}
+
 // AddDetail 添加详情
 func (r *DiagnosticResult) AddDetail(key string, value any) {
 	r.Details[key] = value
@@ -80,6 +88,7 @@ type ReportSummary struct {
 	PassedChecks  int `json:"passedChecks"`
 	WarningChecks int `json:"warningChecks"`
 	FailedChecks  int `json:"failedChecks"`
+	SkippedChecks int `json:"skippedChecks"`
 }
 
 // CalculateSummary 计算报告摘要
@@ -88,6 +97,7 @@ func (r *DiagnosticReport) CalculateSummary() {
 	r.Summary.PassedChecks = 0
 	r.Summary.WarningChecks = 0
 	r.Summary.FailedChecks = 0
+	r.Summary.SkippedChecks = 0
 
 	for _, result := range r.Results {
 		switch result.Status {
@@ -97,6 +107,8 @@ func (r *DiagnosticReport) CalculateSummary() {
 			r.Summary.WarningChecks++
 		case StatusError:
 			r.Summary.FailedChecks++
+		case StatusSkipped:
+			r.Summary.SkippedChecks++
 		}
 	}
 }
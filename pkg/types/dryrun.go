@@ -0,0 +1,31 @@
+package types
+
+// ChangeKind 预演中修改的类型
+type ChangeKind string
+
+const (
+	ChangeCommand  ChangeKind = "command"  // 执行外部命令
+	ChangeRegistry ChangeKind = "registry" // 修改注册表值
+	ChangeFile     ChangeKind = "file"     // 改写文件
+	ChangeAdapter  ChangeKind = "adapter"  // 禁用/启用网卡
+)
+
+// PlannedChange 修复器将要进行的一项修改
+type PlannedChange struct {
+	Kind        ChangeKind `json:"kind"`
+	Target      string     `json:"target"` // 命令行、注册表值路径、文件路径或网卡名称
+	Description string     `json:"description"`
+	OldValue    string     `json:"oldValue,omitempty"`
+	NewValue    string     `json:"newValue,omitempty"`
+	Diff        string     `json:"diff,omitempty"` // 文件修改的逐行差异
+}
+
+// DryRunResult 修复器的预演结果
+type DryRunResult struct {
+	ID             string          `json:"id"`
+	Name           string          `json:"name"`
+	RequiresAdmin  bool            `json:"requiresAdmin"`
+	CreateSnapshot bool            `json:"createSnapshot"` // 执行前会创建快照
+	Changes        []PlannedChange `json:"changes"`
+	Error          string          `json:"error,omitempty"` // 无法预演的原因
+}
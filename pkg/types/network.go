@@ -1,17 +1,24 @@
 package types
 
+import "strings"
+
 // AdapterInfo 网络适配器信息
 type AdapterInfo struct {
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	MACAddress  string   `json:"macAddress"`
-	Status      string   `json:"status"` // Up, Down, Unknown
-	IPAddresses []string `json:"ipAddresses"`
-	SubnetMasks []string `json:"subnetMasks"`
-	Gateways    []string `json:"gateways"`
-	DNSServers  []string `json:"dnsServers"`
-	DHCPEnabled bool     `json:"dhcpEnabled"`
-	DHCPServer  string   `json:"dhcpServer,omitempty"`
+	Index        int      `json:"index"` // 系统接口索引
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	MACAddress   string   `json:"macAddress"`
+	Status       string   `json:"status"` // Up, Down（未连接或已禁用）, Unknown
+	IPAddresses  []string `json:"ipAddresses"`
+	SubnetMasks  []string `json:"subnetMasks"`
+	IPv6         []string `json:"ipv6,omitempty"` // IPv6 地址（含前缀长度）
+	Gateways     []string `json:"gateways"`
+	DNSServers   []string `json:"dnsServers"`
+	DHCPEnabled  bool     `json:"dhcpEnabled"`
+	DHCPServer   string   `json:"dhcpServer,omitempty"`
+	Kind         string   `json:"kind"`         // wired、wireless、virtual、vpn、tunnel
+	Virtual      bool     `json:"virtual"`      // 非物理网卡（虚拟机、VPN、隧道等）
+	DefaultRoute bool     `json:"defaultRoute"` // 承载实际使用的默认路由
 }
 
 // IsValid 检查适配器信息是否有效
@@ -24,6 +31,20 @@ func (a *AdapterInfo) HasIPAddress() bool {
 	return len(a.IPAddresses) > 0
 }
 
+// IsFailing 网卡是否已连接但不可用：只有自动配置地址（169.254.x.x）甚至没有 IPv4 地址
+// 未连接（网线未插、Wi-Fi 未连接）或已禁用的网卡通常是用户有意为之，不算异常
+func (a *AdapterInfo) IsFailing() bool {
+	if a.Status != "Up" {
+		return false
+	}
+	for _, ip := range a.IPAddresses {
+		if !strings.HasPrefix(ip, "169.254.") {
+			return false
+		}
+	}
+	return true
+}
+
 // ProxyConfig 代理配置
 type ProxyConfig struct {
 	Enabled       bool   `json:"enabled"`
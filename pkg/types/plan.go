@@ -0,0 +1,43 @@
+package types
+
+// NeedsRepair 诊断结果是否需要修复（错误、可修复的警告，或因依赖失败而未执行）
+func (r *DiagnosticResult) NeedsRepair() bool {
+	switch r.Status {
+	case StatusError, StatusSkipped:
+		return true
+	case StatusWarning:
+		return r.Repairable
+	}
+	return false
+}
+
+// RepairPlanStep 修复计划中的一步
+type RepairPlanStep struct {
+	RepairerID    string   `json:"repairerId"`
+	Name          string   `json:"name"`
+	Targets       []string `json:"targets"` // 本步骤针对的未通过诊断项
+	Invasiveness  int      `json:"invasiveness"`
+	RequiresAdmin bool     `json:"requiresAdmin"`
+}
+
+// RepairPlan 根据诊断结果生成的修复计划，步骤按影响程度从小到大排列
+type RepairPlan struct {
+	Failing []string         `json:"failing"` // 未通过的诊断项
+	Steps   []RepairPlanStep `json:"steps"`
+}
+
+// RepairPlanStepResult 修复计划中一步的执行结果
+type RepairPlanStepResult struct {
+	RepairPlanStep
+	Skipped  bool               `json:"skipped"` // 针对的诊断项已被前面的步骤解决
+	Repair   *RepairResult      `json:"repair,omitempty"`
+	Rechecks []DiagnosticResult `json:"rechecks"` // 修复后重新运行的诊断项
+}
+
+// RepairPlanResult 修复计划的执行结果
+type RepairPlanResult struct {
+	Steps          []RepairPlanStepResult `json:"steps"`
+	Healthy        bool                   `json:"healthy"`   // 所有未通过的诊断项都已恢复
+	Remaining      []string               `json:"remaining"` // 仍未通过的诊断项
+	RequiresReboot bool                   `json:"requiresReboot"`
+}
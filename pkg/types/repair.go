@@ -4,13 +4,41 @@ import "time"
 
 // RepairResult 修复结果
 type RepairResult struct {
-	ID            string    `json:"id"`
-	Name          string    `json:"name"`
-	Success       bool      `json:"success"`
-	Message       string    `json:"message"`
-	RequireReboot bool      `json:"requireReboot"`
-	Timestamp     time.Time `json:"timestamp"`
-	BackupPath    string    `json:"backupPath,omitempty"`
+	ID            string        `json:"id"`
+	Name          string        `json:"name"`
+	Success       bool          `json:"success"`
+	Message       string        `json:"message"`
+	RequireReboot bool          `json:"requireReboot"`
+	Timestamp     time.Time     `json:"timestamp"`
+	BackupPath    string        `json:"backupPath,omitempty"`
+	Ineffective   bool          `json:"ineffective"`       // 修复命令执行成功，但验证的诊断项仍未通过
+	UndoSeq       int           `json:"undoSeq,omitempty"` // 撤销栈中的序号，0 表示不可单独撤销
+	Verification  *Verification `json:"verification,omitempty"`
+}
+
+// VerificationStatus 修复验证结论
+type VerificationStatus string
+
+const (
+	VerificationPassed        VerificationStatus = "passed"         // 修复后诊断项已通过
+	VerificationIneffective   VerificationStatus = "ineffective"    // 修复后诊断项仍未通过
+	VerificationPendingReboot VerificationStatus = "pending-reboot" // 需要重启后才能验证
+	VerificationSkipped       VerificationStatus = "skipped"        // 修复失败，未验证
+)
+
+// Verification 修复前后的诊断状态
+type Verification struct {
+	Status VerificationStatus `json:"status"`
+	Before []DiagnosticResult `json:"before"`
+	After  []DiagnosticResult `json:"after"`
+}
+
+// UndoEntry 撤销栈中的一次修复
+type UndoEntry struct {
+	Seq        int       `json:"seq"`
+	RepairerID string    `json:"repairerId"`
+	Name       string    `json:"name"`
+	Timestamp  time.Time `json:"timestamp"`
 }
 
 // NewRepairResult 创建新的修复结果
@@ -34,6 +62,13 @@ func (r *RepairResult) SetFailure(message string) {
 	r.Message = message
 }
 
+// SetIneffective 设置为修复无效（命令执行成功但问题仍然存在）
+func (r *RepairResult) SetIneffective(message string) {
+	r.Success = false
+	r.Ineffective = true
+	r.Message = message
+}
+
 // SetRequireReboot 设置需要重启
 func (r *RepairResult) SetRequireReboot() {
 	r.RequireReboot = true
@@ -53,10 +88,11 @@ type RepairReport struct {
 
 // RepairSummary 修复摘要
 type RepairSummary struct {
-	TotalRepairs     int  `json:"totalRepairs"`
-	SuccessfulRepairs int  `json:"successfulRepairs"`
-	FailedRepairs    int  `json:"failedRepairs"`
-	RequiresReboot   bool `json:"requiresReboot"`
+	TotalRepairs       int  `json:"totalRepairs"`
+	SuccessfulRepairs  int  `json:"successfulRepairs"`
+	FailedRepairs      int  `json:"failedRepairs"`
+	IneffectiveRepairs int  `json:"ineffectiveRepairs"` // 计入 FailedRepairs
+	RequiresReboot     bool `json:"requiresReboot"`
 }
 
 // CalculateSummary 计算修复摘要
@@ -64,6 +100,7 @@ func (r *RepairReport) CalculateSummary() {
 	r.Summary.TotalRepairs = len(r.Results)
 	r.Summary.SuccessfulRepairs = 0
 	r.Summary.FailedRepairs = 0
+	r.Summary.IneffectiveRepairs = 0
 	r.Summary.RequiresReboot = false
 
 	for _, result := range r.Results {
@@ -72,6 +109,9 @@ func (r *RepairReport) CalculateSummary() {
 		} else {
 			r.Summary.FailedRepairs++
 		}
+		if result.Ineffective {
+			r.Summary.IneffectiveRepairs++
+		}
 		if result.RequireReboot {
 			r.Summary.RequiresReboot = true
 		}